@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/config"
+	"github.com/gizzahub/gzh-cli-core/errors"
+	"github.com/gizzahub/gzh-cli-core/logger"
+)
+
+// BootstrapOptions configures InstallBootstrap.
+type BootstrapOptions struct {
+	// Config is a pointer to the configuration struct, pre-filled with
+	// defaults. Nil disables config loading.
+	Config interface{}
+
+	// Loader finds the config file when --config is not given.
+	// Defaults to config.NewLoader with the root command's name.
+	Loader *config.Loader
+
+	// Logger receives the level and output derived from the flags.
+	// Defaults to logger.Default().
+	Logger logger.Logger
+}
+
+// Bootstrap holds the parsed global flags and loaded configuration of a
+// root command set up by InstallBootstrap.
+type Bootstrap struct {
+	// Flags are the parsed global flags.
+	Flags GlobalFlags
+	// ConfigFile is the path of the loaded config file, or "" if none was
+	// found. In merge mode it is the file with the highest precedence.
+	ConfigFile string
+	// ConfigFiles are the loaded config files, from lowest to highest
+	// precedence. Without merge mode it holds at most ConfigFile.
+	ConfigFiles []string
+
+	opts    BootstrapOptions
+	loader  *config.Loader
+	applied bool
+}
+
+type bootstrapKey struct{}
+
+// InstallBootstrap adds the global flags to root and installs a
+// PersistentPreRunE that, before any command runs:
+//
+//   - when run as a plugin (see AddPlugins), takes global flags not given
+//     on the command line from the GZH_PLUGIN_FLAG_<FLAG> variables set by
+//     the parent;
+//   - sets the logger level (--debug and --verbose: debug, --quiet: error)
+//     and sends log output to stderr so stdout stays clean for data;
+//   - applies --no-color and --quiet to the package-level output helpers
+//     and to every Output created afterwards (see SetNoColor and SetQuiet);
+//   - loads the config file named by --config, or the first file found by
+//     the Loader's search paths, into opts.Config; in merge mode (see
+//     config.Loader.WithMerge) every file found is merged, with --config
+//     on top;
+//   - applies default tags, environment overrides, the flags of the
+//     running command named by flag tags, and validate tags to opts.Config
+//     (see config.Loader.WithLayers, which it turns on for opts.Loader),
+//     recording the source of each value (see AddConfigCmd);
+//   - stores the Bootstrap in the command context (see BootstrapFrom).
+//
+// An existing PersistentPreRunE or PersistentPreRun on root runs afterwards.
+// Cobra runs only the nearest persistent pre-run hook, so subcommands that
+// define their own should call Apply first (or set
+// cobra.EnableTraverseRunHooks).
+func InstallBootstrap(root *cobra.Command, opts BootstrapOptions) *Bootstrap {
+	b := &Bootstrap{opts: opts}
+	AddGlobalFlags(root, &b.Flags)
+
+	preRunE, preRun := root.PersistentPreRunE, root.PersistentPreRun
+	root.PersistentPreRun = nil
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := b.Apply(cmd); err != nil {
+			return err
+		}
+		switch {
+		case preRunE != nil:
+			return preRunE(cmd, args)
+		case preRun != nil:
+			preRun(cmd, args)
+		}
+		return nil
+	}
+	return b
+}
+
+// Apply applies the global flags and loads the configuration for cmd.
+// It runs once; later calls only attach the Bootstrap to cmd's context.
+func (b *Bootstrap) Apply(cmd *cobra.Command) error {
+	if !b.applied {
+		b.applied = true
+		if err := applyPluginEnv(cmd.Root().PersistentFlags()); err != nil {
+			return errors.Usage(err)
+		}
+		b.applyLogger(cmd)
+
+		if b.Flags.NoColor {
+			SetNoColor(true)
+		}
+		if b.Flags.Quiet {
+			SetQuiet(true)
+		}
+
+		if err := b.loadConfig(cmd); err != nil {
+			return err
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(context.WithValue(ctx, bootstrapKey{}, b))
+	return nil
+}
+
+// Config returns the configuration passed in BootstrapOptions.Config,
+// populated from the config file if one was found.
+func (b *Bootstrap) Config() interface{} {
+	return b.opts.Config
+}
+
+// Loader returns the loader that loaded Config, or nil before the
+// configuration is loaded. Its Explain and Source methods report where
+// each value came from.
+func (b *Bootstrap) Loader() *config.Loader {
+	return b.loader
+}
+
+// BootstrapFrom returns the Bootstrap stored in ctx by InstallBootstrap,
+// typically cmd.Context(), or nil if there is none.
+func BootstrapFrom(ctx context.Context) *Bootstrap {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(bootstrapKey{}).(*Bootstrap)
+	return b
+}
+
+func (b *Bootstrap) applyLogger(cmd *cobra.Command) {
+	log := b.opts.Logger
+	if log == nil {
+		log = logger.Default()
+	}
+	log.SetOutput(cmd.ErrOrStderr())
+	switch {
+	case b.Flags.Debug, b.Flags.Verbose:
+		log.SetLevel(logger.LevelDebug)
+	case b.Flags.Quiet:
+		log.SetLevel(logger.LevelError)
+	}
+}
+
+func (b *Bootstrap) loadConfig(cmd *cobra.Command) error {
+	if b.opts.Config == nil {
+		return nil
+	}
+	loader := b.opts.Loader
+	if loader == nil {
+		loader = config.NewLoader(cmd.Root().Name())
+	}
+	loader.WithLayers(true).WithFlags(cmd.Flags())
+
+	path := b.Flags.Config
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return errors.WithHint(
+				fmt.Errorf("config file %s: %w", path, errors.ErrConfigNotFound),
+				"check the path given to --config")
+		}
+	}
+
+	var files []string
+	switch {
+	case loader.Merging() && path != "":
+		// --config overrides the search paths, and is merged only once
+		// if it is also found there.
+		files = loader.MergeOrder(path)
+	case loader.Merging():
+		files = loader.MergeOrder()
+	case path != "":
+		files = []string{path}
+	default:
+		if found, ok := loader.FindConfigFile(); ok {
+			files = []string{found}
+		}
+	}
+
+	var err error
+	if loader.Merging() || len(files) == 0 {
+		// With no files, this still applies defaults, environment
+		// overrides and validation.
+		err = loader.MergeFiles(b.opts.Config, files...)
+	} else {
+		err = loader.LoadFrom(files[0], b.opts.Config)
+	}
+	if err != nil {
+		if !errors.Is(err, errors.ErrInvalidConfig) {
+			err = errors.Wrap(err, errors.ErrInvalidConfig)
+		}
+		return err
+	}
+	b.loader = loader
+	if len(files) > 0 {
+		b.ConfigFiles = files
+		b.ConfigFile = files[len(files)-1]
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/config"
+	"github.com/gizzahub/gzh-cli-core/errors"
+	"github.com/gizzahub/gzh-cli-core/logger"
+)
+
+type bootstrapTestConfig struct {
+	Name string `yaml:"name"`
+	Port int    `yaml:"port"`
+}
+
+func newBootstrapRoot(t *testing.T, cfg *bootstrapTestConfig, loader *config.Loader) (*cobra.Command, *logger.SimpleLogger, **Bootstrap) {
+	t.Helper()
+	log := logger.New("test")
+	root := &cobra.Command{Use: "app"}
+	InstallBootstrap(root, BootstrapOptions{Config: cfg, Loader: loader, Logger: log})
+
+	var seen *Bootstrap
+	root.AddCommand(&cobra.Command{
+		Use: "run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			seen = BootstrapFrom(cmd.Context())
+			return nil
+		},
+	})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	return root, log, &seen
+}
+
+func TestBootstrap_LoadsConfigFromFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("name: from-file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &bootstrapTestConfig{Name: "default", Port: 8080}
+	root, log, seen := newBootstrapRoot(t, cfg, config.NewLoader("app").WithPaths())
+	root.SetArgs([]string{"run", "--config", path, "--debug"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if cfg.Name != "from-file" || cfg.Port != 8080 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if *seen == nil || (*seen).ConfigFile != path || (*seen).Config() != cfg {
+		t.Fatalf("subcommand should see the loaded config, got %+v", *seen)
+	}
+	if !(*seen).Flags.Debug {
+		t.Error("expected parsed global flags")
+	}
+	if log.GetLevel() != logger.LevelDebug {
+		t.Errorf("logger level = %v, want DEBUG", log.GetLevel())
+	}
+}
+
+func TestBootstrap_SearchPathsAndQuiet(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 9090\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &bootstrapTestConfig{}
+	root, log, seen := newBootstrapRoot(t, cfg, config.NewLoader("app").WithPaths(filepath.Join(dir, "missing.yaml"), path))
+	root.SetArgs([]string{"run", "--quiet"})
+	t.Cleanup(func() { SetQuiet(false) })
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if cfg.Port != 9090 || (*seen).ConfigFile != path {
+		t.Errorf("expected config from search path, got %+v from %q", cfg, (*seen).ConfigFile)
+	}
+	if log.GetLevel() != logger.LevelError {
+		t.Errorf("logger level = %v, want ERROR", log.GetLevel())
+	}
+}
+
+func TestBootstrap_MissingConfigFlag(t *testing.T) {
+	root, _, _ := newBootstrapRoot(t, &bootstrapTestConfig{}, nil)
+	root.SetArgs([]string{"run", "--config", filepath.Join(t.TempDir(), "nope.yaml")})
+
+	err := root.Execute()
+	if !errors.Is(err, errors.ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, got %v", err)
+	}
+	if ExitCode(err) != ExitConfig {
+		t.Errorf("exit code %d, want %d", ExitCode(err), ExitConfig)
+	}
+}
+
+func TestBootstrap_KeepsExistingPreRun(t *testing.T) {
+	root := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	called := false
+	root.PersistentPreRun = func(*cobra.Command, []string) { called = true }
+	InstallBootstrap(root, BootstrapOptions{Logger: logger.NewNop()})
+	root.SetArgs([]string{})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !called {
+		t.Error("existing PersistentPreRun should still run")
+	}
+}
+
+func TestBootstrap_MergeMode(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	override := filepath.Join(dir, "override.yaml")
+	if err := os.WriteFile(user, []byte("name: user\nport: 9000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(override, []byte("port: 9999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &bootstrapTestConfig{}
+	loader := config.NewLoader("app").WithPaths(user).WithMerge(true)
+	root, _, seen := newBootstrapRoot(t, cfg, loader)
+	root.SetArgs([]string{"run", "--config", override})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if cfg.Name != "user" || cfg.Port != 9999 {
+		t.Errorf("--config should be merged over the search paths, got %+v", cfg)
+	}
+	if b := *seen; b.ConfigFile != override || len(b.ConfigFiles) != 2 {
+		t.Errorf("unexpected config files: %q %v", b.ConfigFile, b.ConfigFiles)
+	}
+}
+
+func TestBootstrap_MergeModeConfigInSearchPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte("tags: !append [b]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &struct {
+		Tags []string `yaml:"tags"`
+	}{}
+	root := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	loader := config.NewLoader("app").WithPaths(path).WithMerge(true)
+	b := InstallBootstrap(root, BootstrapOptions{Config: cfg, Loader: loader, Logger: logger.New("test")})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	// The same file, named differently, is merged once.
+	root.SetArgs([]string{"--config", dir + string(filepath.Separator) + "." + string(filepath.Separator) + "app.yaml"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(b.ConfigFiles) != 1 || len(cfg.Tags) != 1 {
+		t.Errorf("expected the file merged once, got %v with tags %v", b.ConfigFiles, cfg.Tags)
+	}
+}
+
+func TestBootstrap_NoColorAndQuietReachNewOutputs(t *testing.T) {
+	t.Cleanup(func() {
+		SetNoColor(false)
+		SetQuiet(false)
+	})
+	root, _, _ := newBootstrapRoot(t, &bootstrapTestConfig{}, config.NewLoader("app").WithPaths())
+	root.SetArgs([]string{"run", "--no-color", "--quiet"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf)
+	if out.colorEnabled(&buf) || out.Stylize("x", StyleRed) != "x" {
+		t.Error("new outputs should not use color after --no-color")
+	}
+	out.Info("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("new outputs should be quiet after --quiet, got %q", buf.String())
+	}
+
+	flagged, err := NewOutputFromFlags(OutputFlags{})
+	if err != nil || flagged.colorMode != ColorNever || !flagged.quiet {
+		t.Errorf("NewOutputFromFlags should inherit the settings, got %v", err)
+	}
+}
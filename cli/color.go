@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorMode controls when colored output is used.
+type ColorMode int
+
+const (
+	// ColorAuto enables color only for terminals, honoring NO_COLOR and TERM=dumb.
+	ColorAuto ColorMode = iota
+	// ColorAlways always emits color codes.
+	ColorAlways
+	// ColorNever never emits color codes.
+	ColorNever
+)
+
+// Style is an ANSI SGR text attribute.
+type Style string
+
+const (
+	StyleBold   Style = "1"
+	StyleDim    Style = "2"
+	StyleRed    Style = "31"
+	StyleGreen  Style = "32"
+	StyleYellow Style = "33"
+	StyleBlue   Style = "34"
+	StyleCyan   Style = "36"
+)
+
+// marker is a status symbol with an ASCII fallback for non-Unicode terminals.
+type marker struct {
+	unicode string
+	ascii   string
+	style   Style
+}
+
+var (
+	markerSuccess = marker{unicode: "✓", ascii: "[OK]", style: StyleGreen}
+	markerError   = marker{unicode: "✗", ascii: "[ERR]", style: StyleRed}
+	markerWarning = marker{unicode: "⚠", ascii: "[WARN]", style: StyleYellow}
+	markerInfo    = marker{unicode: "ℹ", ascii: "[INFO]", style: StyleCyan}
+)
+
+// SetColorMode sets when colored output is used.
+func (o *Output) SetColorMode(mode ColorMode) *Output {
+	o.colorMode = mode
+	return o
+}
+
+// SetNoColor disables colored output, as with the --no-color flag.
+func (o *Output) SetNoColor(noColor bool) *Output {
+	if noColor {
+		o.colorMode = ColorNever
+	} else {
+		o.colorMode = ColorAuto
+	}
+	return o
+}
+
+// Stylize wraps text in the given styles when color is enabled for the
+// output writer. Otherwise text is returned unchanged.
+func (o *Output) Stylize(text string, styles ...Style) string {
+	return o.stylizeFor(o.writer, text, styles...)
+}
+
+func (o *Output) stylizeFor(w io.Writer, text string, styles ...Style) string {
+	if len(styles) == 0 || !o.colorEnabled(w) {
+		return text
+	}
+	codes := make([]string, len(styles))
+	for i, s := range styles {
+		codes[i] = string(s)
+	}
+	return "\033[" + strings.Join(codes, ";") + "m" + text + "\033[0m"
+}
+
+// colorEnabled reports whether color codes should be written to w.
+func (o *Output) colorEnabled(w io.Writer) bool {
+	switch o.colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// marker renders a status marker for w, falling back to ASCII on terminals
+// that cannot display Unicode.
+func (o *Output) marker(w io.Writer, m marker) string {
+	symbol := m.unicode
+	if !unicodeSupported() {
+		symbol = m.ascii
+	}
+	return o.stylizeFor(w, symbol, m.style)
+}
+
+// unicodeSupported reports whether the terminal is expected to render
+// Unicode symbols. TERM=dumb and explicitly non-UTF-8 locales (e.g. LANG=C)
+// fall back to ASCII; an unset locale is assumed to support Unicode.
+func unicodeSupported() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(key); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestOutput_ErrorsToErrorWriter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	out := NewOutput().SetWriter(&stdout).SetErrorWriter(&stderr)
+
+	out.Success("done")
+	out.Info("note")
+	out.Error("failed")
+	out.Warning("careful")
+
+	if stdout.String() != "✓ done\nℹ note\n" {
+		t.Errorf("unexpected stdout: %q", stdout.String())
+	}
+	if stderr.String() != "✗ failed\n⚠ careful\n" {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestOutput_ColorModes(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetColorMode(ColorAlways)
+	out.Success("done")
+	if buf.String() != "\033[32m✓\033[0m done\n" {
+		t.Errorf("expected colored marker, got %q", buf.String())
+	}
+
+	buf.Reset()
+	out.SetNoColor(true).Success("done")
+	if buf.String() != "✓ done\n" {
+		t.Errorf("expected plain marker with --no-color, got %q", buf.String())
+	}
+
+	if got := out.SetColorMode(ColorAuto).Stylize("x", StyleBold); got != "x" {
+		t.Errorf("expected no color for non-terminal writer, got %q", got)
+	}
+}
+
+func TestOutput_NoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	out := NewOutput()
+	if out.colorEnabled(&bytes.Buffer{}) {
+		t.Error("expected NO_COLOR to disable color")
+	}
+	if !out.SetColorMode(ColorAlways).colorEnabled(&bytes.Buffer{}) {
+		t.Error("expected ColorAlways to override NO_COLOR")
+	}
+}
+
+func TestOutput_ASCIIFallback(t *testing.T) {
+	tests := []struct {
+		term, lang string
+		want       string
+	}{
+		{"dumb", "", "[OK] done\n"},
+		{"xterm", "C", "[OK] done\n"},
+		{"xterm", "en_US.UTF-8", "✓ done\n"},
+		{"xterm", "", "✓ done\n"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+
+		var buf bytes.Buffer
+		NewOutput().SetWriter(&buf).Success("done")
+		if buf.String() != tt.want {
+			t.Errorf("TERM=%s LANG=%s: got %q, want %q", tt.term, tt.lang, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestIsTerminal_DevNull(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("terminal detection is a character device check on Windows")
+	}
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Errorf("%s should not be a terminal", os.DevNull)
+	}
+}
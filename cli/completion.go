@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-core/config"
+)
+
+// outputFormats are the --format values offered by shell completion.
+var outputFormats = []cobra.Completion{
+	cobra.CompletionWithDesc("text", "Human-readable text (default)"),
+	cobra.CompletionWithDesc("json", "JSON document"),
+	cobra.CompletionWithDesc("ndjson", "One JSON object per line"),
+	cobra.CompletionWithDesc("yaml", "YAML document"),
+	cobra.CompletionWithDesc("table", "Aligned columns"),
+	cobra.CompletionWithDesc("csv", "Comma-separated values"),
+	cobra.CompletionWithDesc("tsv", "Tab-separated values"),
+	cobra.CompletionWithDesc("llm", "Compact text for language models"),
+	cobra.CompletionWithDesc("template=", "Go template over the whole result, e.g. template={{range .}}{{.Name}} {{end}}"),
+	cobra.CompletionWithDesc("jsonpath=", "JSONPath expression, e.g. jsonpath={.items[*].name}"),
+}
+
+// completionShells are the shells supported by the completion command.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// AddCompletionCommand adds a "completion [bash|zsh|fish|powershell]"
+// command to root that prints the completion script for a shell, with
+// install instructions in its help. It replaces cobra's default completion
+// command and returns the new command.
+func AddCompletionCommand(root *cobra.Command) *cobra.Command {
+	var noDesc bool
+	name := root.Name()
+
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate the shell completion script",
+		Long: fmt.Sprintf(`Generate the completion script for %[1]s for the given shell.
+
+Bash (requires the bash-completion package):
+
+  # current shell
+  source <(%[1]s completion bash)
+
+  # all sessions, Linux
+  %[1]s completion bash > /etc/bash_completion.d/%[1]s
+
+  # all sessions, macOS
+  %[1]s completion bash > $(brew --prefix)/etc/bash_completion.d/%[1]s
+
+Zsh (if completion is not enabled yet, add "autoload -U compinit; compinit"
+to ~/.zshrc):
+
+  %[1]s completion zsh > "${fpath[1]}/_%[1]s"
+
+Fish:
+
+  %[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+PowerShell:
+
+  # current session
+  %[1]s completion powershell | Out-String | Invoke-Expression
+
+  # all sessions: add the line above to your $PROFILE
+
+Start a new shell for the change to take effect.`, name),
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             completionShells,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(w, !noDesc)
+			case "zsh":
+				if noDesc {
+					return root.GenZshCompletionNoDesc(w)
+				}
+				return root.GenZshCompletion(w)
+			case "fish":
+				return root.GenFishCompletion(w, !noDesc)
+			default:
+				if noDesc {
+					return root.GenPowerShellCompletion(w)
+				}
+				return root.GenPowerShellCompletionWithDesc(w)
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&noDesc, "no-descriptions", false, "Disable completion descriptions")
+
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.AddCommand(cmd)
+	return cmd
+}
+
+// CompleteFormats completes --format values. AddOutputFlags registers it
+// for the --format flag.
+func CompleteFormats(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var matches []cobra.Completion
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, format := range outputFormats {
+		if strings.HasPrefix(format, toComplete) {
+			matches = append(matches, format)
+			// No space after "template=", so the expression can follow.
+			if name, _, _ := strings.Cut(format, "\t"); strings.HasSuffix(name, "=") {
+				directive |= cobra.ShellCompDirectiveNoSpace
+			}
+		}
+	}
+	return matches, directive
+}
+
+// CompleteConfigKeys returns a completion function offering the keys of the
+// mapping at path in the config file, e.g. profile names:
+//
+//	cmd.Flags().StringVar(&profile, "profile", "", "Config profile")
+//	cmd.RegisterFlagCompletionFunc("profile", cli.CompleteConfigKeys(nil, "profiles"))
+//
+// path is a dot-separated key path; "" offers the top-level keys. The config
+// file is the one given by --config, or the first one found by loader,
+// which defaults to config.NewLoader with the root command's name. Missing
+// or unreadable config files complete nothing.
+func CompleteConfigKeys(loader *config.Loader, path string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		file := ""
+		if f := cmd.Flags().Lookup("config"); f != nil {
+			file = f.Value.String()
+		}
+		if file == "" {
+			l := loader
+			if l == nil {
+				l = config.NewLoader(cmd.Root().Name())
+			}
+			file, _ = l.FindConfigFile()
+		}
+
+		var keys []cobra.Completion
+		for _, key := range configKeys(file, path) {
+			if strings.HasPrefix(key, toComplete) {
+				keys = append(keys, key)
+			}
+		}
+		return keys, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// configKeys returns the sorted keys of the mapping at path in a YAML file.
+func configKeys(file, path string) []string {
+	if file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var node interface{}
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil
+	}
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			m, ok := node.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			node = m[key]
+		}
+	}
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newCompletionRoot() (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "app"}
+	var global GlobalFlags
+	AddGlobalFlags(root, &global)
+
+	var outFlags OutputFlags
+	var profile string
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	AddOutputFlags(list, &outFlags)
+	list.Flags().StringVar(&profile, "profile", "", "Config profile")
+	_ = list.RegisterFlagCompletionFunc("profile", CompleteConfigKeys(nil, "profiles"))
+	root.AddCommand(list)
+	AddCompletionCommand(root)
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&bytes.Buffer{})
+	return root, &buf
+}
+
+func TestAddCompletionCommand(t *testing.T) {
+	for _, shell := range completionShells {
+		root, buf := newCompletionRoot()
+		root.SetArgs([]string{"completion", shell})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("completion %s: %v", shell, err)
+		}
+		if !strings.Contains(buf.String(), "app") {
+			t.Errorf("completion %s: script does not mention the command", shell)
+		}
+	}
+
+	root, _ := newCompletionRoot()
+	root.SetArgs([]string{"completion", "tcsh"})
+	if err := root.Execute(); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+
+	root, buf := newCompletionRoot()
+	root.SetArgs([]string{"completion", "--help"})
+	_ = root.Execute()
+	if !strings.Contains(buf.String(), "source <(app completion bash)") {
+		t.Errorf("help should include install instructions, got:\n%s", buf.String())
+	}
+}
+
+func TestCompleteFormats(t *testing.T) {
+	root, buf := newCompletionRoot()
+	root.SetArgs([]string{cobra.ShellCompNoDescRequestCmd, "list", "--format", "j"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "json\n") || !strings.Contains(got, "jsonpath=\n") {
+		t.Errorf("expected json completions, got:\n%s", got)
+	}
+	if strings.Contains(got, "yaml") {
+		t.Errorf("completions should match the prefix, got:\n%s", got)
+	}
+	want := fmt.Sprintf(":%d\n", cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace)
+	if !strings.Contains(got, want) {
+		t.Errorf("expected no space after jsonpath=, got:\n%s", got)
+	}
+
+	_, directive := CompleteFormats(nil, nil, "ya")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("plain formats should be followed by a space, got directive %d", directive)
+	}
+}
+
+func TestCompleteConfigKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	data := "profiles:\n  work: {}\n  personal: {}\n  home: {}\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root, buf := newCompletionRoot()
+	root.SetArgs([]string{cobra.ShellCompNoDescRequestCmd, "list", "--config", path, "--profile", ""})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "home\npersonal\nwork\n") {
+		t.Errorf("expected sorted profile names, got:\n%s", got)
+	}
+
+	if keys := configKeys(path, "profiles.work.missing"); keys != nil {
+		t.Errorf("expected no keys for missing path, got %v", keys)
+	}
+	if keys := configKeys(filepath.Join(t.TempDir(), "none.yaml"), ""); keys != nil {
+		t.Errorf("expected no keys for missing file, got %v", keys)
+	}
+}
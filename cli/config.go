@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// AddConfigCmd adds a config command to root with an explain subcommand
+// that prints every setting of the configuration loaded by b, with the
+// source that set it: a file and line, an environment variable, a flag or
+// a default. The table format is used unless --format asks for another.
+// If root already has a config command, explain is added to it.
+func AddConfigCmd(root *cobra.Command, b *Bootstrap) *cobra.Command {
+	cmd := findSubcommand(root, "config")
+	if cmd == nil {
+		cmd = &cobra.Command{
+			Use:   "config",
+			Short: "Inspect the configuration",
+			Args:  cobra.NoArgs,
+		}
+		root.AddCommand(cmd)
+	}
+
+	var flags OutputFlags
+	explain := &cobra.Command{
+		Use:   "explain",
+		Short: "Show each setting and where its value came from",
+		Long: `Show the effective value of each setting and where it came from:
+a config file and line, an environment variable, a command-line flag, or
+a default.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			loader := b.Loader()
+			if loader == nil {
+				return fmt.Errorf("no configuration loaded")
+			}
+			if !flags.FormatSet && flags.Output == "" {
+				flags.Format = "table"
+			}
+			out, err := NewOutputFromFlags(flags)
+			if err != nil {
+				return err
+			}
+			out.SetWriter(cmd.OutOrStdout()).SetErrorWriter(cmd.ErrOrStderr())
+			if err := out.Print(loader.Explain(b.Config())); err != nil {
+				_ = out.Discard()
+				return err
+			}
+			return out.Close()
+		},
+	}
+	AddOutputFlags(explain, &flags)
+	cmd.AddCommand(explain)
+	return explain
+}
+
+// findSubcommand returns the subcommand of cmd called name, or nil.
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/config"
+)
+
+type explainTestConfig struct {
+	Name  string `yaml:"name"`
+	Port  int    `yaml:"port" default:"8080" flag:"port"`
+	Token string `yaml:"token"`
+}
+
+func newExplainRoot(t *testing.T, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("name: from-file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GZH_APP_TOKEN", "abc")
+
+	root := &cobra.Command{Use: "app"}
+	root.PersistentFlags().Int("port", 0, "Port")
+	b := InstallBootstrap(root, BootstrapOptions{Config: &explainTestConfig{}, Loader: config.NewLoader("app").WithPaths(path)})
+	AddConfigCmd(root, b)
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(args)
+	return &out, root.Execute()
+}
+
+func TestConfigExplain(t *testing.T) {
+	out, err := newExplainRoot(t, "config", "explain", "--port", "9000")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	for _, want := range []string{"KEY", "SOURCE", "app.yaml:1", "9000", "flag --port", "env GZH_APP_TOKEN"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestConfigExplain_JSON(t *testing.T) {
+	out, err := newExplainRoot(t, "config", "explain", "--format", "json")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var settings []config.Setting
+	if err := json.Unmarshal(out.Bytes(), &settings); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(settings) != 3 || settings[1].Key != "port" || settings[1].Source.Kind != config.SourceDefault {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+	if settings[2].Source.Kind != config.SourceEnv || settings[2].Source.Env != "GZH_APP_TOKEN" {
+		t.Errorf("unexpected token source: %+v", settings[2].Source)
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// csvListSeparator joins slice elements within a single CSV cell.
+const csvListSeparator = ";"
+
+// csvColumn describes a single flattened CSV column.
+type csvColumn struct {
+	name      string
+	omitEmpty bool
+	// value extracts the cell value from a row.
+	value func(row reflect.Value) reflect.Value
+}
+
+// printCSV prints slices of structs or maps as comma-separated values.
+func (o *Output) printCSV(data interface{}) error {
+	return o.printDelimited(data, ',')
+}
+
+// printTSV prints slices of structs or maps as tab-separated values.
+func (o *Output) printTSV(data interface{}) error {
+	return o.printDelimited(data, '\t')
+}
+
+// printDelimited writes rows as delimited records. Nested structs are
+// flattened into dotted column names, slices are joined with ";", and
+// columns follow struct field order (or sorted keys for maps) so output is
+// stable across runs. Columns tagged `csv:",omitempty"` are dropped when
+// empty in every row.
+func (o *Output) printDelimited(data interface{}, comma rune) error {
+	rows, elemType, ok := tableRows(data)
+	if !ok {
+		return o.printText(data)
+	}
+
+	columns := csvColumns(rows, elemType)
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = make([]string, len(columns))
+		for j, col := range columns {
+			records[i][j] = valueString(col.value(row), csvListSeparator)
+		}
+	}
+
+	keep := make([]int, 0, len(columns))
+	for j, col := range columns {
+		if col.omitEmpty && columnEmpty(records, j) {
+			continue
+		}
+		keep = append(keep, j)
+	}
+	if len(keep) == 0 {
+		return nil
+	}
+
+	w := csv.NewWriter(o.dataWriter())
+	w.Comma = comma
+
+	if !o.noHeader {
+		header := make([]string, len(keep))
+		for i, j := range keep {
+			header[i] = columns[j].name
+		}
+		if err := w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range records {
+		line := make([]string, len(keep))
+		for i, j := range keep {
+			line[i] = record[j]
+		}
+		if err := w.Write(line); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// csvColumns derives flattened columns from the row element type.
+func csvColumns(rows []reflect.Value, elemType reflect.Type) []csvColumn {
+	if elemType.Kind() == reflect.Interface && len(rows) > 0 {
+		elemType = rows[0].Type()
+	}
+
+	switch {
+	case elemType.Kind() == reflect.Struct && !isScalarType(elemType):
+		return flattenStruct(elemType, "", nil, nil)
+	case elemType.Kind() == reflect.Map:
+		return csvMapColumns(rows)
+	default:
+		return []csvColumn{{
+			name:  "value",
+			value: func(row reflect.Value) reflect.Value { return row },
+		}}
+	}
+}
+
+// flattenStruct returns columns for t's fields, recursing into nested
+// structs with dotted names. Embedded structs are promoted without a prefix.
+// Self-referential types are rendered as a single column instead of recursing.
+func flattenStruct(t reflect.Type, prefix string, index []int, parents []reflect.Type) []csvColumn {
+	var columns []csvColumn
+	parents = append(parents, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := csvFieldName(field)
+		if skip {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !isScalarType(ft) && !implementsStringer(ft) && !containsType(parents, ft) {
+			nestedPrefix := prefix + name + "."
+			if field.Anonymous && field.Tag.Get("csv") == "" {
+				nestedPrefix = prefix
+			}
+			columns = append(columns, flattenStruct(ft, nestedPrefix, fieldIndex, parents)...)
+			continue
+		}
+
+		columns = append(columns, csvColumn{
+			name:      prefix + name,
+			omitEmpty: omitEmpty,
+			value: func(row reflect.Value) reflect.Value {
+				return fieldByIndex(row, fieldIndex)
+			},
+		})
+	}
+
+	return columns
+}
+
+// csvFieldName returns the column name for a field from its csv tag,
+// falling back to the json tag and then the Go field name.
+func csvFieldName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag, ok := field.Tag.Lookup("csv")
+	if !ok {
+		tag = field.Tag.Get("json")
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, omitEmpty, false
+}
+
+// csvMapColumns returns one column per map key across all rows, sorted by key.
+func csvMapColumns(rows []reflect.Value) []csvColumn {
+	keys := mapKeys(rows)
+	columns := make([]csvColumn, 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, csvColumn{
+			name:  fmt.Sprint(key.Interface()),
+			value: mapValue(key),
+		})
+	}
+	return columns
+}
+
+// columnEmpty reports whether column j is empty in every record.
+func columnEmpty(records [][]string, j int) bool {
+	for _, record := range records {
+		if record[j] != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// implementsStringer reports whether t or *t implements fmt.Stringer.
+func implementsStringer(t reflect.Type) bool {
+	stringer := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	return t.Implements(stringer) || reflect.PointerTo(t).Implements(stringer)
+}
+
+// containsType reports whether types contains t.
+func containsType(types []reflect.Type, t reflect.Type) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
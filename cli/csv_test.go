@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type csvOwner struct {
+	Login string `csv:"login"`
+	Email string `csv:"email,omitempty"`
+}
+
+type csvRepo struct {
+	Name    string   `csv:"name"`
+	Owner   csvOwner `csv:"owner"`
+	Topics  []string `csv:"topics"`
+	Private bool     `json:"private"`
+	Notes   string   `csv:"notes,omitempty"`
+	Secret  string   `csv:"-"`
+}
+
+func TestCSV_FlattensStructs(t *testing.T) {
+	data := []csvRepo{
+		{Name: "gz-git", Owner: csvOwner{Login: "gizzahub"}, Topics: []string{"git", "cli"}, Secret: "x"},
+		{Name: "gz-core, lib", Owner: csvOwner{Login: "bot"}, Private: true},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("csv")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "name,owner.login,topics,private\n" +
+		"gz-git,gizzahub,git;cli,false\n" +
+		"\"gz-core, lib\",bot,,true\n"
+	if buf.String() != want {
+		t.Errorf("unexpected csv output:\ngot:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestCSV_OmitEmptyKeptWhenSet(t *testing.T) {
+	data := []csvRepo{
+		{Name: "a"},
+		{Name: "b", Notes: "archived"},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("csv").SetNoHeader(true)
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "a,,,false,\nb,,,false,archived\n"
+	if buf.String() != want {
+		t.Errorf("unexpected csv output:\ngot:  %q\nwant: %q", buf.String(), want)
+	}
+}
+
+func TestTSV_Maps(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "a", "updated": time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"name": "b", "count": 2},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("tsv")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "count\tname\tupdated\n" +
+		"\ta\t2025-01-02T03:04:05Z\n" +
+		"2\tb\t\n"
+	if buf.String() != want {
+		t.Errorf("unexpected tsv output:\ngot:  %q\nwant: %q", buf.String(), want)
+	}
+}
+
+func TestCSV_SelfReferentialType(t *testing.T) {
+	type Node struct {
+		Name   string
+		Parent *Node
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("csv")
+	if err := out.Print([]Node{{Name: "root"}}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if buf.String() != "Name,Parent\nroot,\n" {
+		t.Errorf("unexpected csv output: %q", buf.String())
+	}
+}
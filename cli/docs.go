@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// AddDocsCommand adds a hidden "gen-docs" command to root that writes man
+// pages and Markdown reference pages for every command, for use in release
+// builds:
+//
+//	myapp gen-docs --dir docs              # docs/man and docs/markdown
+//	myapp gen-docs --dir man --type man    # man pages only
+func AddDocsCommand(root *cobra.Command) *cobra.Command {
+	var dir string
+	var types []string
+
+	cmd := &cobra.Command{
+		Use:    "gen-docs",
+		Short:  "Generate man pages and Markdown documentation",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			both := len(types) > 1
+			for _, typ := range types {
+				out := dir
+				if both {
+					out = filepath.Join(dir, typ)
+				}
+				var err error
+				switch typ {
+				case "man":
+					err = GenManTree(root, out)
+				case "markdown":
+					err = GenMarkdownTree(root, out)
+				default:
+					return fmt.Errorf("invalid --type %q: must be man or markdown", typ)
+				}
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s documentation to %s\n", typ, out)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&dir, "dir", "docs", "Output directory")
+	cmd.Flags().StringSliceVar(&types, "type", []string{"man", "markdown"}, "Documentation types: man, markdown (both go into subdirectories)")
+	_ = cmd.RegisterFlagCompletionFunc("type", cobra.FixedCompletions([]cobra.Completion{"man", "markdown"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.MarkFlagDirname("dir")
+
+	root.AddCommand(cmd)
+	return cmd
+}
+
+// GenMarkdownTree writes a Markdown page for cmd and each of its visible
+// subcommands to dir, named after the command path, e.g. "myapp_repo_list.md".
+func GenMarkdownTree(cmd *cobra.Command, dir string) error {
+	return genTree(cmd, dir, ".md", genMarkdown)
+}
+
+// GenManTree writes a section 1 man page for cmd and each of its visible
+// subcommands to dir, e.g. "myapp-repo-list.1". The page date honors
+// SOURCE_DATE_EPOCH for reproducible builds.
+func GenManTree(cmd *cobra.Command, dir string) error {
+	return genTree(cmd, dir, ".1", genMan)
+}
+
+// genTree writes one page per documented command, recursively.
+func genTree(cmd *cobra.Command, dir, ext string, gen func(*cobra.Command) []byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create docs directory: %w", err)
+	}
+	for _, c := range docChildren(cmd) {
+		if err := genTree(c, dir, ext, gen); err != nil {
+			return err
+		}
+	}
+
+	sep := "_"
+	if ext == ".1" {
+		sep = "-"
+	}
+	path := filepath.Join(dir, strings.ReplaceAll(cmd.CommandPath(), " ", sep)+ext)
+	if err := os.WriteFile(path, gen(cmd), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// docChildren returns the subcommands that get their own page.
+func docChildren(cmd *cobra.Command) []*cobra.Command {
+	var children []*cobra.Command
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() && !c.IsAdditionalHelpTopicCommand() {
+			children = append(children, c)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name() < children[j].Name() })
+	return children
+}
+
+func genMarkdown(cmd *cobra.Command) []byte {
+	cmd.InitDefaultHelpFlag()
+	var buf bytes.Buffer
+	name := cmd.CommandPath()
+
+	fmt.Fprintf(&buf, "## %s\n\n%s\n\n", name, cmd.Short)
+	fmt.Fprintf(&buf, "### Synopsis\n\n%s\n\n", description(cmd))
+	if cmd.Runnable() {
+		fmt.Fprintf(&buf, "```\n%s\n```\n\n", cmd.UseLine())
+	}
+	if cmd.Example != "" {
+		fmt.Fprintf(&buf, "### Examples\n\n```\n%s\n```\n\n", cmd.Example)
+	}
+	if flags := cmd.NonInheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&buf, "### Options\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+	if flags := cmd.InheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&buf, "### Options inherited from parent commands\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+
+	var related []string
+	if cmd.HasParent() {
+		related = append(related, markdownLink(cmd.Parent()))
+	}
+	for _, c := range docChildren(cmd) {
+		related = append(related, markdownLink(c))
+	}
+	if len(related) > 0 {
+		buf.WriteString("### SEE ALSO\n\n")
+		for _, link := range related {
+			fmt.Fprintf(&buf, "* %s\n", link)
+		}
+	}
+	return buf.Bytes()
+}
+
+func markdownLink(cmd *cobra.Command) string {
+	name := cmd.CommandPath()
+	return fmt.Sprintf("[%s](%s.md)\t - %s", name, strings.ReplaceAll(name, " ", "_"), cmd.Short)
+}
+
+func genMan(cmd *cobra.Command) []byte {
+	cmd.InitDefaultHelpFlag()
+	var buf bytes.Buffer
+	name := cmd.CommandPath()
+	title := strings.ToUpper(strings.ReplaceAll(name, " ", "-"))
+	source := cmd.Root().Name()
+	if v := cmd.Root().Version; v != "" {
+		source += " " + v
+	}
+
+	fmt.Fprintf(&buf, ".TH %q \"1\" %q %q %q\n", title, manDate().Format("Jan 2006"), source, "User Commands")
+	fmt.Fprintf(&buf, ".SH NAME\n%s \\- %s\n", manEscape(strings.ReplaceAll(name, " ", "-")), manEscape(cmd.Short))
+	fmt.Fprintf(&buf, ".SH SYNOPSIS\n\\fB%s\\fP\n", manEscape(cmd.UseLine()))
+	fmt.Fprintf(&buf, ".SH DESCRIPTION\n%s\n", manEscape(description(cmd)))
+	writeManFlags(&buf, "OPTIONS", cmd.NonInheritedFlags().FlagUsages())
+	writeManFlags(&buf, "OPTIONS INHERITED FROM PARENT COMMANDS", cmd.InheritedFlags().FlagUsages())
+	if cmd.Example != "" {
+		fmt.Fprintf(&buf, ".SH EXAMPLE\n.PP\n.RS\n.nf\n%s\n.fi\n.RE\n", manEscape(cmd.Example))
+	}
+
+	var related []string
+	if cmd.HasParent() {
+		related = append(related, manRef(cmd.Parent()))
+	}
+	for _, c := range docChildren(cmd) {
+		related = append(related, manRef(c))
+	}
+	if len(related) > 0 {
+		fmt.Fprintf(&buf, ".SH SEE ALSO\n%s\n", strings.Join(related, ", "))
+	}
+	return buf.Bytes()
+}
+
+// writeManFlags writes the lines of pflag's FlagUsages as a man page
+// section of tagged paragraphs.
+func writeManFlags(buf *bytes.Buffer, section, usages string) {
+	if usages == "" {
+		return
+	}
+	fmt.Fprintf(buf, ".SH %s\n", section)
+	for _, line := range strings.Split(strings.TrimRight(usages, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		// FlagUsages separates the flag from its description with 3+ spaces
+		flag, usage := line, ""
+		if i := strings.Index(line, "   "); i >= 0 {
+			flag, usage = line[:i], strings.TrimSpace(line[i:])
+		}
+		fmt.Fprintf(buf, ".TP\n\\fB%s\\fP\n%s\n", manEscape(flag), manEscape(usage))
+	}
+}
+
+func manRef(cmd *cobra.Command) string {
+	return fmt.Sprintf("\\fB%s(1)\\fP", manEscape(strings.ReplaceAll(cmd.CommandPath(), " ", "-")))
+}
+
+// manEscape escapes text for roff: backslashes, hyphens, and control
+// characters at the start of a line.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// manDate returns the date shown in man pages: SOURCE_DATE_EPOCH if set,
+// otherwise today.
+func manDate() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Now()
+}
+
+// description returns the long description of cmd, or its short one.
+func description(cmd *cobra.Command) string {
+	if cmd.Long != "" {
+		return cmd.Long
+	}
+	return cmd.Short
+}
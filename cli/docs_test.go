@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newDocsRoot() *cobra.Command {
+	root := NewRootCmd(RootConfig{Name: "app", Short: "Test app", Version: "1.2.3"})
+	repo := &cobra.Command{Use: "repo", Short: "Manage repositories"}
+	list := &cobra.Command{
+		Use:     "list",
+		Short:   "List repositories",
+		Long:    "List repositories.\n.Lines starting with a dot are escaped.",
+		Example: "app repo list --format json",
+		Run:     func(*cobra.Command, []string) {},
+	}
+	var outFlags OutputFlags
+	AddOutputFlags(list, &outFlags)
+	repo.AddCommand(list)
+	root.AddCommand(repo, &cobra.Command{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}})
+	AddDocsCommand(root)
+	root.SetOut(&bytes.Buffer{})
+	return root
+}
+
+func TestAddDocsCommand(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	dir := t.TempDir()
+	root := newDocsRoot()
+	root.SetArgs([]string{"gen-docs", "--dir", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{
+		"man/app.1", "man/app-repo.1", "man/app-repo-list.1",
+		"markdown/app.md", "markdown/app_repo.md", "markdown/app_repo_list.md",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	for _, name := range []string{"man/app-secret.1", "man/app-gen-docs.1", "markdown/app_gen-docs.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("hidden command should not be documented: %s", name)
+		}
+	}
+
+	man, _ := os.ReadFile(filepath.Join(dir, "man", "app-repo-list.1"))
+	for _, want := range []string{
+		`.TH "APP-REPO-LIST" "1" "Nov 2023" "app 1.2.3"`,
+		`app\-repo\-list \- List repositories`,
+		`\&.Lines starting`,
+		`\fB\-f, \-\-format string\fP`,
+		`.SH SEE ALSO` + "\n" + `\fBapp\-repo(1)\fP`,
+	} {
+		if !strings.Contains(string(man), want) {
+			t.Errorf("man page missing %q:\n%s", want, man)
+		}
+	}
+
+	md, _ := os.ReadFile(filepath.Join(dir, "markdown", "app_repo.md"))
+	for _, want := range []string{"## app repo", "* [app](app.md)", "* [app repo list](app_repo_list.md)"} {
+		if !strings.Contains(string(md), want) {
+			t.Errorf("markdown page missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestAddDocsCommand_SingleType(t *testing.T) {
+	dir := t.TempDir()
+	root := newDocsRoot()
+	root.SetArgs([]string{"gen-docs", "--dir", dir, "--type", "markdown"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app_repo_list.md")); err != nil {
+		t.Errorf("single type should write into --dir: %v", err)
+	}
+
+	root = newDocsRoot()
+	root.SetArgs([]string{"gen-docs", "--dir", dir, "--type", "html"})
+	if err := root.Execute(); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+	"github.com/gizzahub/gzh-cli-core/llm"
+)
+
+// errorInfo is the structured form of an error in json, yaml and llm output.
+type errorInfo struct {
+	Code    string   `json:"code" yaml:"code"`
+	Message string   `json:"message" yaml:"message"`
+	Hints   []string `json:"hints,omitempty" yaml:"hints,omitempty"`
+	Causes  []string `json:"causes,omitempty" yaml:"causes,omitempty"`
+}
+
+// errorDocument wraps errorInfo so structured output has a top-level
+// "error" key that scripts can test for.
+type errorDocument struct {
+	Error errorInfo `json:"error" yaml:"error"`
+}
+
+func newErrorInfo(err error) errorInfo {
+	return errorInfo{
+		Code:    errors.Code(err),
+		Message: err.Error(),
+		Hints:   errors.Hints(err),
+		Causes:  errors.Causes(err),
+	}
+}
+
+// PrintError renders err in the configured format.
+//
+// The json, ndjson and yaml formats write an {"error": {...}} object with
+// code, message, hints and causes to the output writer, and the llm format
+// writes an ERROR: block, so scripts reading stdout always get parseable
+// output. Other formats write "Error: message" and any hints to the error
+// writer.
+func (o *Output) PrintError(err error) error {
+	if err == nil {
+		return nil
+	}
+	doc := errorDocument{Error: newErrorInfo(err)}
+
+	switch o.format {
+	case "json":
+		return writeJSON(o.writer, doc)
+	case "ndjson", "jsonl":
+		return writeNDJSON(o.writer, doc)
+	case "yaml", "yml":
+		return writeYAML(o.writer, doc)
+	case "llm":
+		_, werr := fmt.Fprint(o.writer, llm.NewFormatter(o.llmOptions).Format(doc))
+		return werr
+	default:
+		if _, werr := fmt.Fprintf(o.errWriter, "Error: %s\n", doc.Error.Message); werr != nil {
+			return werr
+		}
+		for _, hint := range doc.Error.Hints {
+			if _, werr := fmt.Fprintf(o.errWriter, "Hint: %s\n", hint); werr != nil {
+				return werr
+			}
+		}
+		return nil
+	}
+}
+
+// structured reports whether errors are rendered as structured data rather
+// than human-readable text.
+func (o *Output) structured() bool {
+	switch o.format {
+	case "json", "ndjson", "jsonl", "yaml", "yml", "llm":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func newFailingCmd(err error) *cobra.Command {
+	root := &cobra.Command{Use: "app"}
+	flags := &OutputFlags{}
+	cmd := &cobra.Command{
+		Use: "get",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return err
+		},
+	}
+	AddOutputFlags(cmd, flags)
+	root.AddCommand(cmd)
+	return root
+}
+
+func TestPrintError_Formats(t *testing.T) {
+	err := errors.WithHint(errors.WrapWithMessage(errors.ErrNotFound, "repo foo"), "check the name")
+
+	var buf bytes.Buffer
+	if perr := NewOutput().SetWriter(&buf).SetFormat("json").PrintError(err); perr != nil {
+		t.Fatalf("PrintError failed: %v", perr)
+	}
+	var doc struct {
+		Error struct {
+			Code    string   `json:"code"`
+			Message string   `json:"message"`
+			Hints   []string `json:"hints"`
+			Causes  []string `json:"causes"`
+		} `json:"error"`
+	}
+	if jerr := json.Unmarshal(buf.Bytes(), &doc); jerr != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), jerr)
+	}
+	if doc.Error.Code != "NOT_FOUND" || doc.Error.Message != "repo foo: not found" {
+		t.Errorf("unexpected error object: %+v", doc.Error)
+	}
+	if len(doc.Error.Hints) != 1 || len(doc.Error.Causes) != 1 || doc.Error.Causes[0] != "not found" {
+		t.Errorf("unexpected hints or causes: %+v", doc.Error)
+	}
+
+	buf.Reset()
+	_ = NewOutput().SetWriter(&buf).SetFormat("yaml").PrintError(err)
+	if !strings.HasPrefix(buf.String(), "error:\n  code: NOT_FOUND\n") {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	_ = NewOutput().SetWriter(&buf).SetFormat("llm").PrintError(err)
+	if !strings.HasPrefix(buf.String(), "ERROR:\n  CODE: NOT_FOUND\n  MESSAGE: repo foo: not found\n") {
+		t.Errorf("unexpected llm output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	_ = NewOutput().SetWriter(&buf).PrintError(err)
+	if buf.String() != "Error: repo foo: not found\nHint: check the name\n" {
+		t.Errorf("unexpected text output:\n%s", buf.String())
+	}
+}
+
+func TestExecuteWithCode_JSONError(t *testing.T) {
+	root := newFailingCmd(errors.ErrUnauthorized)
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetArgs([]string{"get", "--format", "json"})
+
+	if code := ExecuteWithCode(root); code == 0 {
+		t.Fatal("expected non-zero exit code")
+	}
+	if !strings.Contains(stdout.String(), `"code": "UNAUTHORIZED"`) {
+		t.Errorf("expected JSON error on stdout, got %q", stdout.String())
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected nothing on stderr, got %q", stderr.String())
+	}
+}
+
+func TestExecuteWithCode_TextError(t *testing.T) {
+	root := newFailingCmd(errors.New("boom"))
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetArgs([]string{"get"})
+
+	if code := ExecuteWithCode(root); code == 0 {
+		t.Fatal("expected non-zero exit code")
+	}
+	if !strings.HasPrefix(stderr.String(), "Error: boom\n") {
+		t.Errorf("expected text error on stderr, got %q", stderr.String())
+	}
+	// cobra prints usage to the output writer when one is set
+	if !strings.Contains(stdout.String(), "Usage:") {
+		t.Errorf("expected usage after the error, got %q", stdout.String())
+	}
+	if root.SilenceErrors || root.SilenceUsage {
+		t.Error("ExecuteWithCode should restore the root command's settings")
+	}
+}
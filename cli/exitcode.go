@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// Exit codes returned by ExecuteWithCode. Codes above 2 follow the BSD
+// sysexits.h conventions.
+const (
+	ExitOK          = 0
+	ExitError       = 1  // unclassified failure
+	ExitUsage       = 2  // bad flags or arguments
+	ExitDataErr     = 65 // invalid input data
+	ExitNoInput     = 66 // input not found
+	ExitUnavailable = 69 // service or feature unavailable
+	ExitCantCreate  = 73 // output already exists or cannot be created
+	ExitTempFail    = 75 // temporary failure, e.g. a timeout; retry may succeed
+	ExitNoPerm      = 77 // not authorized or permission denied
+	ExitConfig      = 78 // missing or invalid configuration
+
+	ExitInterrupt = 130 // interrupted by SIGINT or SIGTERM (128 + SIGINT)
+)
+
+// ExitCoder is implemented by errors that choose their own exit code.
+// It takes precedence over registered rules.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// ExitCodeRule maps an error to an exit code, reporting false if it does
+// not apply.
+type ExitCodeRule func(err error) (code int, ok bool)
+
+// defaultExitCodes maps the sentinel errors of the errors package to exit codes.
+var defaultExitCodes = []struct {
+	target error
+	code   int
+}{
+	{errors.ErrUsage, ExitUsage},
+	{errors.ErrInvalidInput, ExitDataErr},
+	{errors.ErrConfigNotFound, ExitConfig},
+	{errors.ErrInvalidConfig, ExitConfig},
+	{errors.ErrNotFound, ExitNoInput},
+	{errors.ErrUnauthorized, ExitNoPerm},
+	{errors.ErrPermission, ExitNoPerm},
+	{errors.ErrTimeout, ExitTempFail},
+	{errors.ErrAlreadyExists, ExitCantCreate},
+	{errors.ErrNotSupported, ExitUnavailable},
+}
+
+var (
+	exitCodeMu    sync.RWMutex
+	exitCodeRules []ExitCodeRule
+)
+
+// RegisterExitCode maps errors matching target (per errors.Is) to code.
+// Registered rules are checked before the defaults, most recent first, so a
+// tool can override the code of a sentinel error or add its own.
+func RegisterExitCode(target error, code int) {
+	RegisterExitCodeRule(func(err error) (int, bool) {
+		return code, errors.Is(err, target)
+	})
+}
+
+// RegisterExitCodeRule registers a custom rule, e.g. one inspecting an error
+// type with errors.As. See RegisterExitCode for precedence.
+func RegisterExitCodeRule(rule ExitCodeRule) {
+	exitCodeMu.Lock()
+	defer exitCodeMu.Unlock()
+	exitCodeRules = append(exitCodeRules, rule)
+}
+
+// ExitCode returns the process exit code for err: ExitOK for nil, the code of
+// an ExitCoder in the chain, the first matching registered rule, the default
+// for a sentinel error, or ExitError.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	exitCodeMu.RLock()
+	rules := exitCodeRules
+	exitCodeMu.RUnlock()
+	for i := len(rules) - 1; i >= 0; i-- {
+		if code, ok := rules[i](err); ok {
+			return code
+		}
+	}
+
+	for _, c := range defaultExitCodes {
+		if errors.Is(err, c.target) {
+			return c.code
+		}
+	}
+	return ExitError
+}
+
+// cobraUsagePrefixes are the starts of error messages cobra and pflag
+// produce for invalid command lines without a hook to mark them.
+var cobraUsagePrefixes = []string{
+	"unknown command ",
+	"unknown flag: ",
+	"unknown shorthand flag: ",
+	"flag needs an argument: ",
+	"invalid argument ",
+	"bad flag syntax: ",
+	"required flag(s) ",
+	"if any flags in the group ",
+	"at least one of the flags in the group ",
+}
+
+// markUsageErrors makes the command-line errors of cmd and its subcommands
+// match errors.ErrUsage: flag parsing errors, argument validation errors and
+// cobra's required flag checks. It returns a function undoing the changes.
+func markUsageErrors(cmd *cobra.Command) (restore func()) {
+	flagErrorFunc := cmd.FlagErrorFunc()
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return errors.Usage(flagErrorFunc(c, err))
+	})
+
+	type savedArgs struct {
+		cmd  *cobra.Command
+		args cobra.PositionalArgs
+	}
+	var saved []savedArgs
+	var wrap func(c *cobra.Command)
+	wrap = func(c *cobra.Command) {
+		if validate := c.Args; validate != nil {
+			saved = append(saved, savedArgs{cmd: c, args: validate})
+			c.Args = func(c *cobra.Command, args []string) error {
+				return errors.Usage(validate(c, args))
+			}
+		}
+		for _, sub := range c.Commands() {
+			wrap(sub)
+		}
+	}
+	wrap(cmd)
+
+	return func() {
+		cmd.SetFlagErrorFunc(flagErrorFunc)
+		for _, s := range saved {
+			s.cmd.Args = s.args
+		}
+	}
+}
+
+// asUsageError marks err as a usage error if it is one of cobra's
+// command-line errors that markUsageErrors cannot intercept.
+func asUsageError(err error) error {
+	for _, prefix := range cobraUsagePrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return errors.Usage(err)
+		}
+	}
+	return err
+}
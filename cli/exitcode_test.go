@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+type exitError struct{ code int }
+
+func (e exitError) Error() string { return fmt.Sprintf("exit %d", e.code) }
+func (e exitError) ExitCode() int { return e.code }
+
+func TestExitCode_Defaults(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("boom"), ExitError},
+		{errors.WrapOp("get", errors.ErrNotFound), ExitNoInput},
+		{errors.ErrConfigNotFound, ExitConfig},
+		{errors.ErrUnauthorized, ExitNoPerm},
+		{errors.ErrPermission, ExitNoPerm},
+		{errors.ErrTimeout, ExitTempFail},
+		{errors.ErrInvalidInput, ExitDataErr},
+		{errors.RequiredFlag("name"), ExitUsage},
+		{errors.WrapOp("run", exitError{code: 42}), 42},
+	}
+
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterExitCode(t *testing.T) {
+	saved := exitCodeRules
+	t.Cleanup(func() { exitCodeRules = saved })
+
+	errQuota := errors.New("quota exceeded")
+	RegisterExitCode(errQuota, 10)
+	RegisterExitCode(errors.ErrNotSupported, 12)
+	RegisterExitCodeRule(func(err error) (int, bool) {
+		var pe *os.PathError
+		return 11, errors.As(err, &pe)
+	})
+
+	if got := ExitCode(errors.WrapOp("upload", errQuota)); got != 10 {
+		t.Errorf("registered sentinel: got %d, want 10", got)
+	}
+	if got := ExitCode(errors.ErrNotSupported); got != 12 {
+		t.Errorf("registered code should override the default, got %d", got)
+	}
+	if _, err := os.Open("/nonexistent/file"); ExitCode(err) != 11 {
+		t.Errorf("registered rule: got %d, want 11", ExitCode(err))
+	}
+	if got := ExitCode(exitError{code: 3}); got != 3 {
+		t.Errorf("ExitCoder should take precedence over rules, got %d", got)
+	}
+}
+
+func TestExecuteWithCode_UsageErrors(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "app"}
+		root.AddCommand(&cobra.Command{
+			Use:  "get NAME",
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error { return nil },
+		})
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		return root
+	}
+
+	tests := [][]string{
+		{"get"},
+		{"get", "a", "--bogus"},
+		{"nope"},
+	}
+	for _, args := range tests {
+		root := newRoot()
+		root.SetArgs(args)
+		if code := ExecuteWithCode(root); code != ExitUsage {
+			t.Errorf("args %v: exit code %d, want %d", args, code, ExitUsage)
+		}
+	}
+
+	root := newRoot()
+	root.SetArgs([]string{"get", "a"})
+	if code := ExecuteWithCode(root); code != ExitOK {
+		t.Errorf("expected success, got %d", code)
+	}
+}
+
+func TestExecuteWithCode_SentinelCode(t *testing.T) {
+	root := newFailingCmd(errors.WrapOp("fetch", errors.ErrTimeout))
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{"get"})
+
+	if code := ExecuteWithCode(root); code != ExitTempFail {
+		t.Errorf("exit code %d, want %d", code, ExitTempFail)
+	}
+}
@@ -2,7 +2,10 @@
 package cli
 
 import (
+	"strconv"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // GlobalFlags holds common flags used across all gzh-cli tools.
@@ -24,18 +27,43 @@ func AddGlobalFlags(cmd *cobra.Command, flags *GlobalFlags) {
 
 	// Mark verbose and quiet as mutually exclusive
 	cmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+
+	_ = cmd.RegisterFlagCompletionFunc("config", func(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return []cobra.Completion{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	})
 }
 
 // OutputFlags holds flags for output formatting.
 type OutputFlags struct {
-	Format string // json, yaml, table, text
-	Output string // output file path (empty for stdout)
+	Format   string // json, ndjson, yaml, table, csv, tsv, llm, template=..., jsonpath=..., text (default)
+	Output   string // output file path (empty for stdout)
+	NoHeader bool   // omit the header row in table and csv output
+	Force    bool   // overwrite an existing output file
+
+	Fields  []string // fields to include, in order
+	Filters []string // FIELD=VALUE expressions items must match
+	SortBy  string   // comma-separated sort fields, "-" prefix for descending
+
+	LLMMaxTokens int // approximate token budget for llm output (0: unlimited)
+
+	// FormatSet reports whether --format was given. Otherwise the format is
+	// inferred from the Output file extension. AddOutputFlags sets it.
+	FormatSet bool
 }
 
 // AddOutputFlags adds output formatting flags to a command.
 func AddOutputFlags(cmd *cobra.Command, flags *OutputFlags) {
-	cmd.Flags().StringVarP(&flags.Format, "format", "f", "text", "Output format (json, yaml, table, text)")
+	flags.Format = "text"
+	cmd.Flags().VarP(&formatValue{format: &flags.Format, set: &flags.FormatSet}, "format", "f",
+		"Output format: text, json, ndjson, yaml, table, csv, tsv, llm, template=TEMPLATE, jsonpath=EXPR; inferred from --output when not given")
 	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().BoolVar(&flags.NoHeader, "no-header", false, "Omit the header row in table and csv output")
+	cmd.Flags().StringSliceVar(&flags.Fields, "fields", nil, "Comma-separated fields to include (e.g. name,status)")
+	cmd.Flags().StringArrayVar(&flags.Filters, "filter", nil, "Only include items matching FIELD=VALUE (also !=, ~=, >, >=, <, <=; repeatable)")
+	cmd.Flags().StringVar(&flags.SortBy, "sort-by", "", "Sort items by fields (e.g. status,-updated_at)")
+	cmd.Flags().IntVar(&flags.LLMMaxTokens, "llm-max-tokens", 0, "Approximate token budget for llm output (0: unlimited)")
+	bindForceFlag(cmd, &flags.Force)
+	_ = cmd.RegisterFlagCompletionFunc("format", CompleteFormats)
 }
 
 // DryRunFlags holds flags for dry-run mode.
@@ -47,7 +75,7 @@ type DryRunFlags struct {
 // AddDryRunFlags adds dry-run related flags to a command.
 func AddDryRunFlags(cmd *cobra.Command, flags *DryRunFlags) {
 	cmd.Flags().BoolVar(&flags.DryRun, "dry-run", false, "Show what would be done without making changes")
-	cmd.Flags().BoolVar(&flags.Force, "force", false, "Force operation without confirmation")
+	bindForceFlag(cmd, &flags.Force)
 }
 
 // ConfirmFlags holds flags for confirmation prompts.
@@ -59,3 +87,84 @@ type ConfirmFlags struct {
 func AddConfirmFlags(cmd *cobra.Command, flags *ConfirmFlags) {
 	cmd.Flags().BoolVarP(&flags.Yes, "yes", "y", false, "Assume yes to all prompts")
 }
+
+// bindForceFlag binds --force to target. The flag is shared when several
+// flag groups (e.g. OutputFlags and DryRunFlags) are added to one command.
+// A bool --force the command defined itself keeps working and also sets
+// target; a --force of another type is left alone.
+func bindForceFlag(cmd *cobra.Command, target *bool) {
+	if f := cmd.Flags().Lookup("force"); f != nil {
+		switch v := f.Value.(type) {
+		case *sharedBoolValue:
+			v.targets = append(v.targets, target)
+		default:
+			if v.Type() == "bool" {
+				*target, _ = strconv.ParseBool(v.String())
+				f.Value = &sharedBoolValue{base: v, targets: []*bool{target}}
+			}
+		}
+		return
+	}
+	value := &sharedBoolValue{targets: []*bool{target}}
+	f := cmd.Flags().VarPF(value, "force", "", "Force operation without confirmation")
+	f.NoOptDefVal = "true"
+}
+
+// formatValue is the pflag.Value of --format. It records whether the flag
+// was given, so that the "text" default can give way to the format of the
+// --output file.
+type formatValue struct {
+	format *string
+	set    *bool
+}
+
+func (v *formatValue) Set(s string) error {
+	*v.format = s
+	*v.set = true
+	return nil
+}
+
+func (v *formatValue) String() string {
+	if v.format == nil {
+		return ""
+	}
+	return *v.format
+}
+
+func (v *formatValue) Type() string { return "string" }
+
+// sharedBoolValue is a pflag.Value that sets several bool targets at once,
+// and the value of a flag defined by the command, if any.
+type sharedBoolValue struct {
+	base    pflag.Value
+	targets []*bool
+}
+
+func (v *sharedBoolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	if v.base != nil {
+		if err := v.base.Set(s); err != nil {
+			return err
+		}
+	}
+	for _, t := range v.targets {
+		*t = b
+	}
+	return nil
+}
+
+func (v *sharedBoolValue) String() string {
+	if v.base != nil {
+		return v.base.String()
+	}
+	if len(v.targets) == 0 {
+		return "false"
+	}
+	return strconv.FormatBool(*v.targets[0])
+}
+
+func (v *sharedBoolValue) Type() string     { return "bool" }
+func (v *sharedBoolValue) IsBoolFlag() bool { return true }
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// printJSONPath evaluates a JSONPath template against the JSON form of data.
+//
+// The supported subset covers field access (.name, ['name']), array indexes
+// ([0], [-1]), wildcards ([*], .*), slices ([1:3]) and recursive descent
+// (..name). Expressions are wrapped in braces and may be mixed with literal
+// text: "{.name}: {.status}". Multiple results are joined with spaces.
+func (o *Output) printJSONPath(expr string, data interface{}) error {
+	if strings.TrimSpace(expr) == "" {
+		return fmt.Errorf("jsonpath format requires an expression, e.g. jsonpath={.name}")
+	}
+
+	root, err := toJSONValue(data)
+	if err != nil {
+		return err
+	}
+
+	result, err := evalJSONPathTemplate(expr, root)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	_, err = fmt.Fprint(o.dataWriter(), result)
+	return err
+}
+
+// toJSONValue converts data to its generic JSON representation, exactly as
+// printJSON would encode it.
+func toJSONValue(data interface{}) (interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// evalJSONPathTemplate expands every {expression} in tmpl. A template without
+// braces is treated as a single expression.
+func evalJSONPathTemplate(tmpl string, root interface{}) (string, error) {
+	if !strings.Contains(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+
+	var sb strings.Builder
+	for len(tmpl) > 0 {
+		start := strings.Index(tmpl, "{")
+		if start < 0 {
+			sb.WriteString(tmpl)
+			break
+		}
+		sb.WriteString(tmpl[:start])
+
+		end := strings.Index(tmpl[start:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("jsonpath: unclosed expression in %q", tmpl)
+		}
+		expr := tmpl[start+1 : start+end]
+		tmpl = tmpl[start+end+1:]
+
+		values, err := evalJSONPath(expr, root)
+		if err != nil {
+			return "", err
+		}
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			parts = append(parts, jsonPathString(v))
+		}
+		sb.WriteString(strings.Join(parts, " "))
+	}
+	return sb.String(), nil
+}
+
+// jsonPathString renders a result: strings and numbers as-is, everything
+// else as compact JSON.
+func jsonPathString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(raw)
+	}
+}
+
+// jsonPathSegment is one step of a parsed path.
+type jsonPathSegment struct {
+	recursive bool
+	wildcard  bool
+	field     string
+	hasField  bool
+	index     int
+	hasIndex  bool
+	start     *int
+	end       *int
+}
+
+// evalJSONPath evaluates a single path expression against root.
+func evalJSONPath(expr string, root interface{}) ([]interface{}, error) {
+	segments, err := parseJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{root}
+	for _, seg := range segments {
+		var next []interface{}
+		for _, v := range values {
+			if seg.recursive {
+				for _, d := range descendants(v) {
+					next = append(next, seg.apply(d)...)
+				}
+				continue
+			}
+			next = append(next, seg.apply(v)...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// parseJSONPath splits an expression into segments.
+func parseJSONPath(expr string) ([]jsonPathSegment, error) {
+	p := strings.TrimSpace(expr)
+	p = strings.TrimPrefix(p, "$")
+
+	var segments []jsonPathSegment
+	for len(p) > 0 {
+		seg := jsonPathSegment{}
+
+		switch {
+		case strings.HasPrefix(p, ".."):
+			seg.recursive = true
+			p = p[2:]
+		case p[0] == '.':
+			p = p[1:]
+		}
+		if p == "" {
+			break
+		}
+
+		if p[0] == '[' {
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed bracket in %q", expr)
+			}
+			if err := seg.parseBracket(p[1:end]); err != nil {
+				return nil, fmt.Errorf("jsonpath: %w in %q", err, expr)
+			}
+			p = p[end+1:]
+		} else {
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			name := p[:end]
+			p = p[end:]
+			if name == "*" {
+				seg.wildcard = true
+			} else {
+				seg.field, seg.hasField = name, true
+			}
+		}
+
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// parseBracket parses the contents of [...].
+func (s *jsonPathSegment) parseBracket(content string) error {
+	content = strings.TrimSpace(content)
+	switch {
+	case content == "*":
+		s.wildcard = true
+	case len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0]:
+		s.field, s.hasField = content[1:len(content)-1], true
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return fmt.Errorf("invalid slice %q", content)
+			}
+			if i == 0 {
+				s.start = &n
+			} else {
+				s.end = &n
+			}
+		}
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return fmt.Errorf("invalid index %q", content)
+		}
+		s.index, s.hasIndex = n, true
+	}
+	return nil
+}
+
+// apply evaluates the segment against a single value.
+func (s jsonPathSegment) apply(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if s.hasField {
+			if child, ok := val[s.field]; ok {
+				return []interface{}{child}
+			}
+			return nil
+		}
+		if s.wildcard {
+			keys := make([]string, 0, len(val))
+			for k := range val {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			result := make([]interface{}, 0, len(keys))
+			for _, k := range keys {
+				result = append(result, val[k])
+			}
+			return result
+		}
+	case []interface{}:
+		n := len(val)
+		switch {
+		case s.wildcard:
+			return val
+		case s.hasIndex:
+			i := s.index
+			if i < 0 {
+				i += n
+			}
+			if i < 0 || i >= n {
+				return nil
+			}
+			return []interface{}{val[i]}
+		case s.start != nil || s.end != nil:
+			start, end := 0, n
+			if s.start != nil {
+				start = clampIndex(*s.start, n)
+			}
+			if s.end != nil {
+				end = clampIndex(*s.end, n)
+			}
+			if start >= end {
+				return nil
+			}
+			return val[start:end]
+		}
+	}
+	return nil
+}
+
+// clampIndex resolves negative indexes and clamps i to [0, n].
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// descendants returns v and every value nested within it, in document order.
+func descendants(v interface{}) []interface{} {
+	result := []interface{}{v}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			result = append(result, descendants(val[k])...)
+		}
+	case []interface{}:
+		for _, child := range val {
+			result = append(result, descendants(child)...)
+		}
+	}
+	return result
+}
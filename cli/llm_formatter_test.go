@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/gizzahub/gzh-cli-core/llm"
 )
 
 func TestLLMFormatter_BasicStruct(t *testing.T) {
@@ -299,28 +301,6 @@ func TestLLMFormatter_Pointer(t *testing.T) {
 	}
 }
 
-func TestLLMFormatter_FieldNameConversion(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"Name", "NAME"},
-		{"FieldName", "FIELD_NAME"},
-		{"HTTPStatus", "HTTP_STATUS"},
-		{"ID", "ID"},
-		{"URLPath", "URL_PATH"},
-		{"XMLParser", "XML_PARSER"},
-	}
-
-	f := &llmFormatter{}
-	for _, tt := range tests {
-		result := f.fieldNameToLabel(tt.input)
-		if result != tt.expected {
-			t.Errorf("fieldNameToLabel(%s) = %s, want %s", tt.input, result, tt.expected)
-		}
-	}
-}
-
 func TestLLMFormatter_MaxDepth(t *testing.T) {
 	type Deep struct {
 		Level int
@@ -400,3 +380,206 @@ func TestOutput_SetFormat_LLM(t *testing.T) {
 		t.Errorf("LLM format should work with uppercase, got: %s", buf.String())
 	}
 }
+
+func TestLLMFormatter_StructTags(t *testing.T) {
+	type Meta struct {
+		Owner  string
+		Region string
+	}
+	type Data struct {
+		Name     string `llm:"REPO"`
+		Enabled  bool   `llm:",keepzero"`
+		Retries  int    `llm:"RETRY_COUNT,keepzero"`
+		Note     string `llm:",keepzero"`
+		Internal string `llm:",omit"`
+		Token    string `llm:"-"`
+		Meta     Meta   `llm:",inline"`
+	}
+
+	data := Data{
+		Name:     "gz-git",
+		Internal: "debug",
+		Token:    "secret",
+		Meta:     Meta{Owner: "gizzahub"},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "REPO: gz-git\nENABLED: false\nRETRY_COUNT: 0\nNOTE: \"\"\nOWNER: gizzahub\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+type llmStatus int
+
+func (s llmStatus) MarshalLLM() string {
+	if s == 0 {
+		return "pending"
+	}
+	return "done"
+}
+
+type llmSummary struct {
+	Lines []string
+}
+
+func (s *llmSummary) MarshalLLM() string {
+	return strings.Join(s.Lines, "\n")
+}
+
+func TestLLMFormatter_Marshaler(t *testing.T) {
+	type Task struct {
+		Name     string
+		Status   llmStatus
+		Statuses []llmStatus
+		Summary  *llmSummary
+	}
+
+	data := Task{
+		Name:     "build",
+		Statuses: []llmStatus{0, 1},
+		Summary:  &llmSummary{Lines: []string{"step 1 ok", "step 2 ok"}},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "NAME: build\nSTATUS: pending\nSTATUSES: pending | done\nSUMMARY:\n  step 1 ok\n  step 2 ok\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestLLMFormatter_MapKeysSorted(t *testing.T) {
+	data := map[string]int{"zeta": 1, "alpha": 2, "mid": 3, "beta": 4}
+
+	for i := 0; i < 5; i++ {
+		var buf bytes.Buffer
+		out := NewOutput().SetWriter(&buf).SetFormat("llm")
+		if err := out.Print(data); err != nil {
+			t.Fatalf("Print failed: %v", err)
+		}
+		if buf.String() != "alpha: 2\nbeta: 4\nmid: 3\nzeta: 1\n" {
+			t.Fatalf("expected sorted keys, got: %q", buf.String())
+		}
+	}
+}
+
+func TestLLMFormatter_BudgetTruncatesSlices(t *testing.T) {
+	type Repo struct {
+		Name string
+	}
+	type Result struct {
+		Total int
+		Repos []Repo
+	}
+
+	data := Result{Total: 100}
+	for i := 0; i < 100; i++ {
+		data.Repos = append(data.Repos, Repo{Name: strings.Repeat("r", 20)})
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm").SetLLMMaxTokens(100)
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	output := buf.String()
+	if len(output) > 100*llm.CharsPerToken {
+		t.Errorf("output exceeds budget (%d chars): %s", len(output), output)
+	}
+	if !strings.HasPrefix(output, "TOTAL: 100\n") {
+		t.Errorf("expected top-level fields first, got: %s", output)
+	}
+	if !strings.Contains(output, "… 9") || !strings.Contains(output, "more items") {
+		t.Errorf("expected more-items marker, got: %s", output)
+	}
+	if !strings.Contains(output, "ELIDED: 9") {
+		t.Errorf("expected elision summary, got: %s", output)
+	}
+
+	var again bytes.Buffer
+	_ = NewOutput().SetWriter(&again).SetFormat("llm").SetLLMMaxTokens(100).Print(data)
+	if again.String() != output {
+		t.Error("expected deterministic output")
+	}
+}
+
+func TestLLMFormatter_BudgetShortensStrings(t *testing.T) {
+	type Doc struct {
+		Title string
+		Body  string
+	}
+
+	data := Doc{Title: "readme", Body: strings.Repeat("word ", 200)}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm").SetLLMMaxTokens(80)
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "TITLE: readme\n") {
+		t.Errorf("expected title to be kept, got: %s", output)
+	}
+	if !strings.Contains(output, "…\n") || !strings.Contains(output, "ELIDED: 1 strings shortened") {
+		t.Errorf("expected shortened body, got: %s", output)
+	}
+}
+
+func TestLLMFormatter_BudgetReducesDepth(t *testing.T) {
+	type Node struct {
+		Name     string
+		Children []Node
+	}
+
+	leaf := Node{Name: strings.Repeat("leaf", 10)}
+	data := Node{Name: "root", Children: []Node{{Name: "child", Children: []Node{leaf, leaf}}}}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm").SetLLMMaxTokens(12)
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "NAME: root\n") {
+		t.Errorf("expected root name to be kept, got: %s", output)
+	}
+	if !strings.Contains(output, "ELIDED:") {
+		t.Errorf("expected elision summary, got: %s", output)
+	}
+	if len(output) > 12*llm.CharsPerToken {
+		t.Errorf("output exceeds budget (%d chars): %s", len(output), output)
+	}
+}
+
+func TestOutput_SetLLMOptions(t *testing.T) {
+	type Tree struct {
+		Name   string
+		Parent *Tree
+	}
+	root := &Tree{Name: "root"}
+	root.Parent = root
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("llm").SetLLMOptions(llm.Options{IndentSize: 4})
+	if err := out.Print(map[string]interface{}{"tree": root}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	expected := "tree:\n    NAME: root\n    PARENT: <ref tree>\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output.\nGot:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+}
@@ -8,94 +8,200 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-core/llm"
 )
 
+// LLMMarshaler is implemented by types that control their own rendering in
+// the llm output format. See llm.Marshaler.
+type LLMMarshaler = llm.Marshaler
+
 // Output handles formatted output.
 type Output struct {
-	writer io.Writer
-	format string
+	writer    io.Writer
+	errWriter io.Writer
+	colorMode ColorMode
+	format    string
+	formatArg string // argument of template=... and jsonpath=... formats
+	noHeader  bool
+	maxWidth  int
+	file      *atomicFile
+	query     query
+	quiet     bool
+
+	llmOptions llm.Options
 }
 
-// NewOutput creates a new Output with default stdout writer.
+// NewOutput creates a new Output with default stdout writer. It starts
+// with the package-level color and quiet settings (see SetNoColor and
+// SetQuiet), so --no-color and --quiet apply to every Output once
+// InstallBootstrap has run.
 func NewOutput() *Output {
 	return &Output{
-		writer: os.Stdout,
-		format: "text",
+		writer:    os.Stdout,
+		errWriter: os.Stderr,
+		format:    "text",
+		colorMode: defaultColorMode,
+		quiet:     defaultQuiet,
 	}
 }
 
-// SetWriter sets the output writer.
+// SetWriter sets the output writer for both results and diagnostics.
+// Use SetErrorWriter afterwards to send errors and warnings elsewhere.
 func (o *Output) SetWriter(w io.Writer) *Output {
 	o.writer = w
+	o.errWriter = w
+	return o
+}
+
+// SetErrorWriter sets the writer for errors and warnings (stderr by default).
+func (o *Output) SetErrorWriter(w io.Writer) *Output {
+	o.errWriter = w
 	return o
 }
 
 // SetFormat sets the output format.
+// Parameterized formats take their argument after "=", e.g.
+// "template={{.Name}}" or "jsonpath={.items[*].name}"; the argument keeps its case.
 func (o *Output) SetFormat(format string) *Output {
-	o.format = strings.ToLower(format)
+	name, arg, _ := strings.Cut(format, "=")
+	o.format = strings.ToLower(name)
+	o.formatArg = arg
+	return o
+}
+
+// SetNoHeader disables the header row in table output.
+func (o *Output) SetNoHeader(noHeader bool) *Output {
+	o.noHeader = noHeader
+	return o
+}
+
+// SetMaxWidth sets the maximum table width.
+// Zero (the default) uses the terminal width, or no limit when not a terminal.
+func (o *Output) SetMaxWidth(width int) *Output {
+	o.maxWidth = width
 	return o
 }
 
 // Print prints data in the configured format.
+// Field selection, filters and sorting are applied before formatting.
 func (o *Output) Print(data interface{}) error {
+	data, err := o.query.apply(data)
+	if err != nil {
+		return err
+	}
+
 	switch o.format {
 	case "json":
 		return o.printJSON(data)
+	case "ndjson", "jsonl":
+		return o.printNDJSON(data)
 	case "yaml", "yml":
 		return o.printYAML(data)
 	case "llm":
 		return o.printLLM(data)
+	case "table":
+		return o.printTable(data)
+	case "csv":
+		return o.printCSV(data)
+	case "tsv":
+		return o.printTSV(data)
+	case "template", "go-template":
+		return o.printTemplate(o.formatArg, data)
+	case "jsonpath":
+		return o.printJSONPath(o.formatArg, data)
 	default:
 		return o.printText(data)
 	}
 }
 
+// SetLLMMaxTokens limits llm output to roughly maxTokens tokens.
+// Long slices and strings are shortened deterministically and an ELIDED line
+// reports what was left out. Zero (the default) means no limit.
+func (o *Output) SetLLMMaxTokens(maxTokens int) *Output {
+	o.llmOptions.MaxTokens = maxTokens
+	return o
+}
+
+// SetLLMOptions configures llm output: nesting depth, indent size and token
+// budget. It replaces any limit set with SetLLMMaxTokens.
+func (o *Output) SetLLMOptions(opts llm.Options) *Output {
+	o.llmOptions = opts
+	return o
+}
+
 // printLLM prints data in LLM-friendly compact format.
 func (o *Output) printLLM(data interface{}) error {
-	formatter := &llmFormatter{}
-	output := formatter.format(data, 0)
+	output := llm.NewFormatter(o.llmOptions).Format(data)
 	if output == "" {
 		return nil
 	}
-	_, err := fmt.Fprint(o.writer, output)
+	_, err := fmt.Fprint(o.dataWriter(), output)
 	return err
 }
 
+// dataWriter returns the destination for printed data: the output file if
+// one was configured, otherwise the regular writer.
+func (o *Output) dataWriter() io.Writer {
+	if o.file != nil {
+		return o.file
+	}
+	return o.writer
+}
+
 func (o *Output) printJSON(data interface{}) error {
-	enc := json.NewEncoder(o.writer)
+	return writeJSON(o.dataWriter(), data)
+}
+
+func (o *Output) printYAML(data interface{}) error {
+	return writeYAML(o.dataWriter(), data)
+}
+
+// writeJSON writes data to w as indented JSON.
+func writeJSON(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
 	return enc.Encode(data)
 }
 
-func (o *Output) printYAML(data interface{}) error {
-	enc := yaml.NewEncoder(o.writer)
+// writeYAML writes data to w as a YAML document.
+func writeYAML(w io.Writer, data interface{}) error {
+	enc := yaml.NewEncoder(w)
 	enc.SetIndent(2)
 	return enc.Encode(data)
 }
 
 func (o *Output) printText(data interface{}) error {
-	_, err := fmt.Fprintln(o.writer, data)
+	_, err := fmt.Fprintln(o.dataWriter(), data)
 	return err
 }
 
 // Success prints a success message with checkmark.
 func (o *Output) Success(msg string, args ...interface{}) {
-	fmt.Fprintf(o.writer, "✓ "+msg+"\n", args...)
+	o.status(o.writer, markerSuccess, msg, args...)
 }
 
-// Error prints an error message with X mark.
+// Error prints an error message with X mark to the error writer.
 func (o *Output) Error(msg string, args ...interface{}) {
-	fmt.Fprintf(o.writer, "✗ "+msg+"\n", args...)
+	o.status(o.errWriter, markerError, msg, args...)
 }
 
-// Warning prints a warning message.
+// Warning prints a warning message to the error writer.
 func (o *Output) Warning(msg string, args ...interface{}) {
-	fmt.Fprintf(o.writer, "⚠ "+msg+"\n", args...)
+	o.status(o.errWriter, markerWarning, msg, args...)
 }
 
-// Info prints an info message.
+// Info prints an info message. It is suppressed by SetQuiet.
 func (o *Output) Info(msg string, args ...interface{}) {
-	fmt.Fprintf(o.writer, "ℹ "+msg+"\n", args...)
+	if o.quiet {
+		return
+	}
+	o.status(o.writer, markerInfo, msg, args...)
+}
+
+// status prints a message prefixed with a status marker.
+func (o *Output) status(w io.Writer, m marker, msg string, args ...interface{}) {
+	fmt.Fprintf(w, "%s %s\n", o.marker(w, m), fmt.Sprintf(msg, args...))
 }
 
 // Line prints a plain message.
@@ -105,12 +211,18 @@ func (o *Output) Line(msg string, args ...interface{}) {
 
 // DryRun prints a dry-run notice.
 func (o *Output) DryRun() {
-	fmt.Fprintln(o.writer, "[DRY-RUN] No changes will be made")
+	fmt.Fprintf(o.writer, "%s No changes will be made\n", o.stylizeFor(o.writer, "[DRY-RUN]", StyleYellow))
 }
 
 // Package-level convenience functions
 
-var defaultOutput = NewOutput()
+var (
+	// defaultColorMode and defaultQuiet are inherited by new Outputs.
+	defaultColorMode ColorMode
+	defaultQuiet     bool
+
+	defaultOutput = NewOutput()
+)
 
 // Success prints a success message.
 func Success(msg string, args ...interface{}) {
@@ -136,3 +248,17 @@ func Info(msg string, args ...interface{}) {
 func DryRun() {
 	defaultOutput.DryRun()
 }
+
+// SetNoColor disables colored output for the package-level helpers and
+// for Outputs created afterwards.
+func SetNoColor(noColor bool) {
+	defaultOutput.SetNoColor(noColor)
+	defaultColorMode = defaultOutput.colorMode
+}
+
+// SetQuiet suppresses info messages and progress indicators of the
+// package-level helpers and of Outputs created afterwards.
+func SetQuiet(quiet bool) {
+	defaultOutput.SetQuiet(quiet)
+	defaultQuiet = quiet
+}
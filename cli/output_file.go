@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// NewOutputFromFlags creates an Output configured from parsed OutputFlags.
+//
+// When flags.Output is set, printed data is written to that file instead of
+// stdout. The file is written to a temporary file in the same directory and
+// only renamed into place by Close, so readers never observe a partial file.
+// Unless --format was given (flags.FormatSet), a Format of "text" or ""
+// gives way to the format inferred from the file extension.
+// An existing file is not overwritten unless flags.Force is set.
+func NewOutputFromFlags(flags OutputFlags) (*Output, error) {
+	out := NewOutput().
+		SetNoHeader(flags.NoHeader).
+		SetFields(flags.Fields...).
+		SetFilters(flags.Filters...).
+		SetSortBy(flags.SortBy).
+		SetLLMMaxTokens(flags.LLMMaxTokens)
+
+	format := flags.Format
+	if !flags.FormatSet && (format == "" || format == "text") && flags.Output != "" {
+		if inferred := formatFromExtension(flags.Output); inferred != "" {
+			format = inferred
+		}
+	}
+	if format != "" {
+		out.SetFormat(format)
+	}
+
+	if flags.Output != "" {
+		f, err := createAtomicFile(flags.Output, flags.Force)
+		if err != nil {
+			return nil, err
+		}
+		out.file = f
+	}
+
+	return out, nil
+}
+
+// Close commits the output file, if any. It is a no-op for stdout output.
+func (o *Output) Close() error {
+	if o.file == nil {
+		return nil
+	}
+	f := o.file
+	o.file = nil
+	return f.commit()
+}
+
+// Discard removes the pending output file without replacing the target.
+// Use it instead of Close when the command fails.
+func (o *Output) Discard() error {
+	if o.file == nil {
+		return nil
+	}
+	f := o.file
+	o.file = nil
+	return f.discard()
+}
+
+// formatFromExtension infers the output format from a file name.
+func formatFromExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".csv":
+		return "csv"
+	case ".tsv":
+		return "tsv"
+	default:
+		return ""
+	}
+}
+
+// atomicFile writes to a temporary file that replaces path on commit.
+type atomicFile struct {
+	path  string
+	force bool
+	tmp   *os.File
+}
+
+// createAtomicFile opens a temporary file next to path.
+func createAtomicFile(path string, force bool) (*atomicFile, error) {
+	if !force {
+		if err := checkOverwrite(path); err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	return &atomicFile{path: path, force: force, tmp: tmp}, nil
+}
+
+// Write writes to the temporary file.
+func (f *atomicFile) Write(p []byte) (int, error) {
+	return f.tmp.Write(p)
+}
+
+// commit flushes the temporary file and renames it over the target path.
+// An existing target keeps its permissions; new files are created 0644.
+func (f *atomicFile) commit() error {
+	if err := f.tmp.Sync(); err != nil {
+		_ = f.discard()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := f.tmp.Close(); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(f.path); err == nil {
+		if !f.force {
+			_ = os.Remove(f.tmp.Name())
+			return checkOverwrite(f.path)
+		}
+		mode = fi.Mode().Perm()
+	}
+
+	if err := os.Chmod(f.tmp.Name(), mode); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := os.Rename(f.tmp.Name(), f.path); err != nil {
+		_ = os.Remove(f.tmp.Name())
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// discard closes and removes the temporary file.
+func (f *atomicFile) discard() error {
+	_ = f.tmp.Close()
+	if err := os.Remove(f.tmp.Name()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove temporary output file: %w", err)
+	}
+	return nil
+}
+
+// checkOverwrite returns an error if path already exists.
+func checkOverwrite(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("output file %s: %w (use --force to overwrite)", path, errors.ErrAlreadyExists)
+	}
+	return nil
+}
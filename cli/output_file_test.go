@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func TestNewOutputFromFlags_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	out, err := NewOutputFromFlags(OutputFlags{Output: path})
+	if err != nil {
+		t.Fatalf("NewOutputFromFlags failed: %v", err)
+	}
+	if err := out.Print(map[string]string{"key": "value"}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected file to be written only on Close")
+	}
+
+	if err := out.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), `"key": "value"`) {
+		t.Errorf("expected JSON inferred from extension, got: %s", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected no leftover temp files, got %d entries", len(entries))
+	}
+}
+
+func TestNewOutputFromFlags_ExplicitFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+
+	out, err := NewOutputFromFlags(OutputFlags{Output: path, Format: "yaml"})
+	if err != nil {
+		t.Fatalf("NewOutputFromFlags failed: %v", err)
+	}
+	if err := out.Print(map[string]string{"key": "value"}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "key: value\n" {
+		t.Errorf("expected YAML output, got: %s", data)
+	}
+}
+
+func TestNewOutputFromFlags_FormatFlag(t *testing.T) {
+	parse := func(args ...string) OutputFlags {
+		t.Helper()
+		cmd := &cobra.Command{Use: "test"}
+		var flags OutputFlags
+		AddOutputFlags(cmd, &flags)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return flags
+	}
+
+	if flags := parse(); flags.Format != "text" || flags.FormatSet {
+		t.Errorf("expected text default, got %q (set %v)", flags.Format, flags.FormatSet)
+	}
+
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--output", filepath.Join(dir, "a.json")}, "json"},
+		{[]string{"--output", filepath.Join(dir, "b.json"), "--format", "text"}, "text"},
+	} {
+		out, err := NewOutputFromFlags(parse(tc.args...))
+		if err != nil {
+			t.Fatalf("NewOutputFromFlags failed: %v", err)
+		}
+		if out.format != tc.want {
+			t.Errorf("%v: format %q, want %q", tc.args, out.format, tc.want)
+		}
+		_ = out.Discard()
+	}
+}
+
+func TestNewOutputFromFlags_RefusesOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.yaml")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	_, err := NewOutputFromFlags(OutputFlags{Output: path})
+	if !errors.Is(err, errors.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	out, err := NewOutputFromFlags(OutputFlags{Output: path, Force: true})
+	if err != nil {
+		t.Fatalf("NewOutputFromFlags with force failed: %v", err)
+	}
+	if err := out.Print(map[string]int{"count": 1}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("expected original permissions to be kept, got %v", fi.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "count: 1\n" {
+		t.Errorf("unexpected content: %s", data)
+	}
+}
+
+func TestOutput_Discard(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+
+	out, err := NewOutputFromFlags(OutputFlags{Output: path})
+	if err != nil {
+		t.Fatalf("NewOutputFromFlags failed: %v", err)
+	}
+	_ = out.Print("partial")
+	if err := out.Discard(); err != nil {
+		t.Fatalf("Discard failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected empty directory after Discard, got %d entries", len(entries))
+	}
+}
+
+func TestForceFlag_Shared(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	outFlags := &OutputFlags{}
+	dryFlags := &DryRunFlags{}
+	AddOutputFlags(cmd, outFlags)
+	AddDryRunFlags(cmd, dryFlags)
+
+	if err := cmd.ParseFlags([]string{"--force"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	if !outFlags.Force || !dryFlags.Force {
+		t.Errorf("expected --force to set both flag groups, got output=%v dry-run=%v", outFlags.Force, dryFlags.Force)
+	}
+}
+
+func TestForceFlag_DefinedByCommand(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	var force bool
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite")
+	outFlags := &OutputFlags{}
+	AddOutputFlags(cmd, outFlags)
+
+	if err := cmd.ParseFlags([]string{"--force"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	if !force || !outFlags.Force {
+		t.Errorf("expected --force to set both, got command=%v output=%v", force, outFlags.Force)
+	}
+
+	// A --force of another type is left to the command.
+	cmd = &cobra.Command{Use: "test"}
+	cmd.Flags().String("force", "", "Force mode")
+	outFlags = &OutputFlags{}
+	AddOutputFlags(cmd, outFlags)
+	if err := cmd.ParseFlags([]string{"--force", "all"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	if outFlags.Force {
+		t.Error("a string --force should not set OutputFlags.Force")
+	}
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// ActionKind is the kind of change an Action makes.
+type ActionKind string
+
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionDelete ActionKind = "delete"
+)
+
+// ActionStatus is the state of an Action in a Plan.
+type ActionStatus string
+
+const (
+	StatusPlanned ActionStatus = "planned" // dry run, or not run yet
+	StatusDone    ActionStatus = "done"
+	StatusFailed  ActionStatus = "failed"
+	StatusSkipped ActionStatus = "skipped" // not run because an earlier step failed or was canceled
+)
+
+// Action is one intended change recorded in a Plan.
+type Action struct {
+	Kind   ActionKind   `json:"action" yaml:"action" table:"ACTION"`
+	Target string       `json:"target" yaml:"target"`
+	Diff   string       `json:"diff,omitempty" yaml:"diff,omitempty" table:"-"`
+	Status ActionStatus `json:"status" yaml:"status"`
+	Error  string       `json:"error,omitempty" yaml:"error,omitempty"`
+
+	run func(ctx context.Context) error
+}
+
+// Plan collects the changes a command intends to make, then either renders
+// them (dry run) or executes them in order.
+//
+//	plan := cli.NewPlan(out, flags.DryRun)
+//	for _, repo := range stale {
+//		plan.Delete(repo.Path, func(ctx context.Context) error { return os.RemoveAll(repo.Path) })
+//	}
+//	return plan.Run(ctx)
+type Plan struct {
+	out             *Output
+	dryRun          bool
+	continueOnError bool
+	actions         []*Action
+}
+
+// NewPlan creates a plan reporting to out. With dryRun set, Run only
+// renders the plan.
+func NewPlan(out *Output, dryRun bool) *Plan {
+	return &Plan{out: out, dryRun: dryRun}
+}
+
+// SetContinueOnError makes Run execute the remaining steps after a failure
+// instead of skipping them.
+func (p *Plan) SetContinueOnError(continueOnError bool) *Plan {
+	p.continueOnError = continueOnError
+	return p
+}
+
+// Create records the creation of target.
+func (p *Plan) Create(target string, fn func(ctx context.Context) error) *Action {
+	return p.Add(ActionCreate, target, "", fn)
+}
+
+// Update records a change to target. diff describes the change, typically
+// as unified diff lines, and is shown in the plan.
+func (p *Plan) Update(target, diff string, fn func(ctx context.Context) error) *Action {
+	return p.Add(ActionUpdate, target, diff, fn)
+}
+
+// Delete records the removal of target.
+func (p *Plan) Delete(target string, fn func(ctx context.Context) error) *Action {
+	return p.Add(ActionDelete, target, "", fn)
+}
+
+// Add records an action of any kind.
+func (p *Plan) Add(kind ActionKind, target, diff string, fn func(ctx context.Context) error) *Action {
+	action := &Action{Kind: kind, Target: target, Diff: diff, Status: StatusPlanned, run: fn}
+	p.actions = append(p.actions, action)
+	return action
+}
+
+// Actions returns the recorded actions.
+func (p *Plan) Actions() []*Action {
+	return p.actions
+}
+
+// Run renders the plan in dry-run mode, and otherwise executes each action
+// in order. The text format reports each step as it completes followed by a
+// summary; other formats print the actions with their final status.
+//
+// After a failure the remaining steps are skipped unless continue-on-error is
+// set, and steps are skipped once ctx is canceled. The returned error wraps
+// every step error, so errors.Is and ExitCode see the underlying causes.
+func (p *Plan) Run(ctx context.Context) error {
+	if p.dryRun {
+		return p.render()
+	}
+
+	text := p.out.isText()
+	var errs []error
+	for _, action := range p.actions {
+		if ctx.Err() != nil || (len(errs) > 0 && !p.continueOnError) {
+			action.Status = StatusSkipped
+			continue
+		}
+
+		if err := p.runAction(ctx, action); err != nil {
+			action.Status = StatusFailed
+			action.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s %s: %w", action.Kind, action.Target, err))
+			if text {
+				p.out.Error("Failed to %s %s: %v", action.Kind, action.Target, err)
+			}
+			continue
+		}
+		action.Status = StatusDone
+		if text {
+			p.out.Success("%s %s", pastTense(action.Kind), action.Target)
+		}
+	}
+
+	if text {
+		p.out.Line("%s", p.summary())
+	} else if err := p.out.Print(p.actions); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d steps failed: %w", p.count(StatusFailed), len(p.actions), errors.Join(errs...))
+}
+
+func (p *Plan) runAction(ctx context.Context, action *Action) error {
+	if action.run == nil {
+		return nil
+	}
+	return action.run(ctx)
+}
+
+// render writes the plan without executing it.
+func (p *Plan) render() error {
+	if !p.out.isText() {
+		return p.out.Print(p.actions)
+	}
+
+	p.out.DryRun()
+	if len(p.actions) == 0 {
+		p.out.Line("No changes.")
+		return nil
+	}
+	for _, action := range p.actions {
+		symbol, style := actionSymbol(action.Kind)
+		p.out.Line("%s %s %s", p.out.Stylize(symbol, style), action.Kind, action.Target)
+		for _, line := range strings.Split(strings.TrimRight(action.Diff, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			p.out.Line("    %s", p.out.Stylize(line, diffLineStyle(line)...))
+		}
+	}
+
+	var parts []string
+	for _, kind := range []ActionKind{ActionCreate, ActionUpdate, ActionDelete} {
+		if n := p.countKind(kind); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d to %s", n, kind))
+		}
+	}
+	if other := len(p.actions) - p.countKind(ActionCreate) - p.countKind(ActionUpdate) - p.countKind(ActionDelete); other > 0 {
+		parts = append(parts, fmt.Sprintf("%d other", other))
+	}
+	p.out.Line("Plan: %s", strings.Join(parts, ", "))
+	return nil
+}
+
+// summary describes the outcome of Run.
+func (p *Plan) summary() string {
+	parts := []string{fmt.Sprintf("%d succeeded", p.count(StatusDone))}
+	if n := p.count(StatusFailed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", n))
+	}
+	if n := p.count(StatusSkipped); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", n))
+	}
+	return "Summary: " + strings.Join(parts, ", ")
+}
+
+func (p *Plan) count(status ActionStatus) int {
+	n := 0
+	for _, action := range p.actions {
+		if action.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (p *Plan) countKind(kind ActionKind) int {
+	n := 0
+	for _, action := range p.actions {
+		if action.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// actionSymbol returns the plan marker for an action kind.
+func actionSymbol(kind ActionKind) (string, Style) {
+	switch kind {
+	case ActionCreate:
+		return "+", StyleGreen
+	case ActionUpdate:
+		return "~", StyleYellow
+	case ActionDelete:
+		return "-", StyleRed
+	default:
+		return "*", StyleCyan
+	}
+}
+
+// diffLineStyle colors added and removed lines of a diff.
+func diffLineStyle(line string) []Style {
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return []Style{StyleGreen}
+	case strings.HasPrefix(line, "-"):
+		return []Style{StyleRed}
+	default:
+		return nil
+	}
+}
+
+// pastTense returns the completed form of an action, e.g. "Deleted".
+func pastTense(kind ActionKind) string {
+	verb := string(kind)
+	if verb == "" {
+		return "Done"
+	}
+	if strings.HasSuffix(verb, "e") {
+		verb += "d"
+	} else {
+		verb += "ed"
+	}
+	return strings.ToUpper(verb[:1]) + verb[1:]
+}
+
+// isText reports whether the format is human-readable text rather than data.
+func (o *Output) isText() bool {
+	return o.format == "" || o.format == "text"
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func newTestPlan(dryRun bool, format string) (*Plan, *bytes.Buffer, *[]string) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat(format).SetColorMode(ColorNever)
+	plan := NewPlan(out, dryRun)
+
+	var ran []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+	plan.Create("repo-a", record("create", nil))
+	plan.Update("config.yaml", "-branch: main\n+branch: develop\n", record("update", nil))
+	plan.Delete("repo-b", record("delete", nil))
+	return plan, &buf, &ran
+}
+
+func TestPlan_DryRunText(t *testing.T) {
+	plan, buf, ran := newTestPlan(true, "text")
+
+	if err := plan.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(*ran) != 0 {
+		t.Errorf("dry run executed actions: %v", *ran)
+	}
+
+	expected := `[DRY-RUN] No changes will be made
++ create repo-a
+~ update config.yaml
+    -branch: main
+    +branch: develop
+- delete repo-b
+Plan: 1 to create, 1 to update, 1 to delete
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output.\nGot:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestPlan_DryRunJSON(t *testing.T) {
+	plan, buf, _ := newTestPlan(true, "json")
+
+	if err := plan.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var actions []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &actions); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(actions) != 3 || actions[1]["action"] != "update" || actions[1]["status"] != "planned" || actions[1]["diff"] == "" {
+		t.Errorf("unexpected plan: %v", actions)
+	}
+}
+
+func TestPlan_Execute(t *testing.T) {
+	plan, buf, ran := newTestPlan(false, "text")
+
+	if err := plan.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if strings.Join(*ran, ",") != "create,update,delete" {
+		t.Errorf("unexpected execution order: %v", *ran)
+	}
+	output := buf.String()
+	for _, want := range []string{"Created repo-a", "Updated config.yaml", "Deleted repo-b", "Summary: 3 succeeded\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
+
+func TestPlan_FailureSkipsRemaining(t *testing.T) {
+	var buf bytes.Buffer
+	plan := NewPlan(NewOutput().SetWriter(&buf), false)
+	plan.Delete("a", func(context.Context) error { return errors.ErrPermission })
+	plan.Delete("b", func(context.Context) error { t.Error("step after failure should be skipped"); return nil })
+
+	err := plan.Run(context.Background())
+	if !errors.Is(err, errors.ErrPermission) {
+		t.Errorf("expected wrapped step error, got %v", err)
+	}
+	if ExitCode(err) != ExitNoPerm {
+		t.Errorf("exit code %d, want %d", ExitCode(err), ExitNoPerm)
+	}
+	if !strings.Contains(buf.String(), "Summary: 0 succeeded, 1 failed, 1 skipped") {
+		t.Errorf("unexpected summary:\n%s", buf.String())
+	}
+	if plan.Actions()[0].Status != StatusFailed || plan.Actions()[1].Status != StatusSkipped {
+		t.Errorf("unexpected statuses: %s, %s", plan.Actions()[0].Status, plan.Actions()[1].Status)
+	}
+}
+
+func TestPlan_ContinueOnError(t *testing.T) {
+	var buf bytes.Buffer
+	plan := NewPlan(NewOutput().SetWriter(&buf).SetFormat("json"), false).SetContinueOnError(true)
+	plan.Create("a", func(context.Context) error { return errors.New("boom") })
+	plan.Create("b", nil)
+
+	if err := plan.Run(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(buf.String(), `"status": "done"`) || !strings.Contains(buf.String(), `"error": "boom"`) {
+		t.Errorf("expected per-step results as JSON, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+const (
+	// pluginGroupID groups plugin commands in help output.
+	pluginGroupID = "plugins"
+	// pluginParentEnv names the root command that runs a plugin. Plugins
+	// only read the flag variables when it is set.
+	pluginParentEnv = "GZH_PLUGIN_PARENT"
+	// pluginEnvPrefix prefixes the variables carrying parent flags to plugins.
+	pluginEnvPrefix = "GZH_PLUGIN_FLAG_"
+)
+
+// Plugin is an external executable run as a subcommand.
+type Plugin struct {
+	// Name is the subcommand name, e.g. "git" for "gz-git".
+	Name string
+	// Path is the path of the executable.
+	Path string
+}
+
+// PluginOptions configures AddPlugins.
+type PluginOptions struct {
+	// Prefix is the executable name prefix. Defaults to "<root>-".
+	Prefix string
+	// Dirs are searched before PATH. Defaults to PluginDir(root name).
+	Dirs []string
+}
+
+// PluginDir returns the plugins directory under the app's config directory:
+// $XDG_CONFIG_HOME/<app>/plugins, or ~/.config/<app>/plugins.
+func PluginDir(appName string) string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName, "plugins")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", appName, "plugins")
+}
+
+// DiscoverPlugins finds executables named prefix+NAME in dirs and then in
+// the directories of PATH, sorted by name. When several share a name, the
+// first one found wins, as in a shell.
+func DiscoverPlugins(prefix string, dirs ...string) []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range append(dirs, filepath.SplitList(os.Getenv("PATH"))...) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name(), prefix)
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// AddPlugins discovers plugins for root and adds each as a subcommand, so
+// "gz git status" runs "gz-git status". Call it after adding the built-in
+// commands: plugins named like an existing command are skipped.
+//
+// A plugin receives its arguments unchanged, except for root's persistent
+// flags (such as GlobalFlags), which may appear anywhere before "--". They
+// are parsed by root and passed to the plugin in the environment as
+// GZH_PLUGIN_FLAG_<FLAG>, e.g. GZH_PLUGIN_FLAG_DEBUG=true, along with
+// GZH_PLUGIN_PARENT=<root>; InstallBootstrap reads them back in plugins
+// built with this package. The plugin's exit code
+// becomes the exit code of root, and shell completion is forwarded to
+// plugins built with cobra.
+func AddPlugins(root *cobra.Command, opts PluginOptions) []Plugin {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = root.Name() + "-"
+	}
+	dirs := opts.Dirs
+	if dirs == nil {
+		if dir := PluginDir(root.Name()); dir != "" {
+			dirs = []string{dir}
+		}
+	}
+
+	var added []Plugin
+	for _, plugin := range DiscoverPlugins(prefix, dirs...) {
+		if hasSubcommand(root, plugin.Name) {
+			continue
+		}
+		if !root.ContainsGroup(pluginGroupID) {
+			root.AddGroup(&cobra.Group{ID: pluginGroupID, Title: "Plugin Commands:"})
+		}
+		root.AddCommand(newPluginCmd(root, plugin))
+		added = append(added, plugin)
+	}
+	return added
+}
+
+func newPluginCmd(root *cobra.Command, plugin Plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                plugin.Name,
+		Short:              fmt.Sprintf("Run the %s plugin", filepath.Base(plugin.Path)),
+		GroupID:            pluginGroupID,
+		Annotations:        map[string]string{"plugin": plugin.Path},
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			args, err := parsePersistentFlags(root.PersistentFlags(), args)
+			if err != nil {
+				return errors.Usage(err)
+			}
+			return runPlugin(cmd, root, plugin, args)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+			return completePlugin(root, plugin, args, toComplete)
+		},
+	}
+}
+
+// runPlugin runs the plugin with the command's standard streams. When the
+// command context is canceled, the plugin is asked to stop (see
+// stopPlugin) and killed if it is still running after the cleanup timeout.
+func runPlugin(cmd *cobra.Command, root *cobra.Command, plugin Plugin, args []string) error {
+	ctx := cmd.Context()
+	c := exec.CommandContext(ctx, plugin.Path, args...)
+	c.Cancel = func() error { return stopPlugin(c.Process, context.Cause(ctx)) }
+	cleanupMu.Lock()
+	c.WaitDelay = cleanupTimeout
+	cleanupMu.Unlock()
+	c.Stdin = cmd.InOrStdin()
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	c.Env = append(os.Environ(), pluginEnv(root)...)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// The plugin has already reported its error.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &pluginExitError{name: plugin.Name, code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", plugin.Name, err)
+	}
+	return nil
+}
+
+// stopPlugin asks a plugin to shut down gracefully after its context was
+// canceled for cause. An interrupt is taken to come from the terminal,
+// which has sent it to the plugin too, as they share a process group; it is
+// not sent again, since a second one would make the plugin skip its
+// cleanup. Other signals are forwarded, and other causes sent as an
+// interrupt. Where signals cannot be sent, the plugin is killed.
+func stopPlugin(p *os.Process, cause error) error {
+	sig := os.Interrupt
+	var sigErr *signalError
+	if errors.As(cause, &sigErr) {
+		if sigErr.sig == os.Interrupt {
+			return nil
+		}
+		sig = sigErr.sig
+	}
+	if err := p.Signal(sig); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
+
+// completePlugin asks a cobra-based plugin for completions through its
+// hidden __complete command.
+func completePlugin(root *cobra.Command, plugin Plugin, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	args, err := parsePersistentFlags(root.PersistentFlags(), args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	var stdout bytes.Buffer
+	c := exec.Command(plugin.Path, append(append([]string{cobra.ShellCompRequestCmd}, args...), toComplete)...)
+	c.Stdout = &stdout
+	c.Env = append(os.Environ(), pluginEnv(root)...)
+	if err := c.Run(); err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	// The output is one completion per line followed by ":<directive>".
+	lines := strings.Split(strings.TrimRight(stdout.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, ":") {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	directive, err := strconv.Atoi(last[1:])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	var completions []cobra.Completion
+	for _, line := range lines[:len(lines)-1] {
+		if line != "" {
+			completions = append(completions, line)
+		}
+	}
+	return completions, cobra.ShellCompDirective(directive)
+}
+
+// pluginExitError carries a plugin's exit code to ExecuteWithCode.
+type pluginExitError struct {
+	name string
+	code int
+}
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin %s exited with code %d", e.name, e.code)
+}
+
+// ExitCode implements ExitCoder. A plugin killed by a signal exits with
+// ExitError.
+func (e *pluginExitError) ExitCode() int {
+	if e.code < 0 {
+		return ExitError
+	}
+	return e.code
+}
+
+// parsePersistentFlags sets the flags of fs found in args before "--" and
+// returns the remaining arguments. Flags are accepted in the forms cobra
+// accepts: --name, --name=value, --name value, -x value, -xvalue, -x=value
+// and clusters of shorthands such as -vq. Arguments that are not entirely
+// made of flags of fs, such as a cluster with other letters, are left to
+// the plugin.
+func parsePersistentFlags(fs *pflag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(rest, args[i:]...), nil
+		}
+
+		var flags []flagArg
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			if f := fs.Lookup(name); f != nil {
+				flags = []flagArg{{flag: f, value: value, hasValue: hasValue}}
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			flags = shorthandArgs(fs, arg[1:])
+		}
+		if flags == nil {
+			rest = append(rest, arg)
+			continue
+		}
+
+		for _, fa := range flags {
+			f, value := fa.flag, fa.value
+			if !fa.hasValue {
+				switch {
+				case f.NoOptDefVal != "":
+					value = f.NoOptDefVal
+				case i+1 < len(args):
+					i++
+					value = args[i]
+				default:
+					return nil, fmt.Errorf("flag needs an argument: %s", arg)
+				}
+			}
+			if err := fs.Set(f.Name, value); err != nil {
+				return nil, fmt.Errorf("invalid argument %q for %q flag: %w", value, arg, err)
+			}
+		}
+	}
+	return rest, nil
+}
+
+// flagArg is a flag found in an argument, with its value if attached.
+type flagArg struct {
+	flag     *pflag.Flag
+	value    string
+	hasValue bool
+}
+
+// shorthandArgs splits a cluster of shorthand flags, the argument without
+// its "-", e.g. "vq" or "cfile". A flag taking a value ends the cluster and
+// takes the rest of it, if any, as its value. It returns nil if a letter
+// is not a flag of fs.
+func shorthandArgs(fs *pflag.FlagSet, cluster string) []flagArg {
+	var flags []flagArg
+	for j := 0; j < len(cluster); j++ {
+		f := fs.ShorthandLookup(cluster[j : j+1])
+		if f == nil {
+			return nil
+		}
+		rest := cluster[j+1:]
+		switch {
+		case strings.HasPrefix(rest, "="):
+			return append(flags, flagArg{flag: f, value: rest[1:], hasValue: true})
+		case f.NoOptDefVal == "" && rest != "":
+			return append(flags, flagArg{flag: f, value: rest, hasValue: true})
+		}
+		flags = append(flags, flagArg{flag: f})
+	}
+	return flags
+}
+
+// pluginEnv returns the plugin marker, GZH_PLUGIN_FLAG_<FLAG>=value for
+// each persistent flag of root that was set, and NO_COLOR for --no-color so
+// that plugins not built with this package disable color too.
+func pluginEnv(root *cobra.Command) []string {
+	env := []string{pluginParentEnv + "=" + root.Name()}
+	root.PersistentFlags().Visit(func(f *pflag.Flag) {
+		value := f.Value.String()
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			value = strings.Join(sv.GetSlice(), ",")
+		}
+		env = append(env, pluginEnvName(f.Name)+"="+value)
+		if f.Name == "no-color" && value == "true" {
+			env = append(env, "NO_COLOR=1")
+		}
+	})
+	return env
+}
+
+// applyPluginEnv sets the flags of fs not given on the command line from
+// the variables set by pluginEnv, when this binary runs as a plugin, i.e.
+// GZH_PLUGIN_PARENT is set.
+func applyPluginEnv(fs *pflag.FlagSet) error {
+	if os.Getenv(pluginParentEnv) == "" {
+		return nil
+	}
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed || err != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(pluginEnvName(f.Name)); ok {
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("invalid %s: %w", pluginEnvName(f.Name), setErr)
+			}
+		}
+	})
+	return err
+}
+
+// pluginEnvName returns the variable name for a flag, e.g.
+// GZH_PLUGIN_FLAG_NO_COLOR.
+func pluginEnvName(flag string) string {
+	return pluginEnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// pluginName returns the subcommand name of a plugin file.
+func pluginName(file, prefix string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".exe", ".bat", ".cmd":
+			file = strings.TrimSuffix(file, filepath.Ext(file))
+		default:
+			return "", false
+		}
+	}
+	name := strings.TrimPrefix(file, prefix)
+	if name == file || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// isExecutable reports whether path is a regular file that can be executed.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// hasSubcommand reports whether cmd has a subcommand or alias called name.
+func hasSubcommand(cmd *cobra.Command, name string) bool {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
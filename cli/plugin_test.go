@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// writePlugin writes an executable shell script to dir.
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newPluginRoot(t *testing.T, dir string) (*cobra.Command, *GlobalFlags, *bytes.Buffer) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	t.Setenv("PATH", "")
+
+	root := &cobra.Command{Use: "app"}
+	var flags GlobalFlags
+	AddGlobalFlags(root, &flags)
+	root.AddCommand(&cobra.Command{Use: "builtin", Run: func(*cobra.Command, []string) {}})
+	AddPlugins(root, PluginOptions{Dirs: []string{dir}})
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &flags, &buf
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	want := writePlugin(t, first, "app-hello", "")
+	writePlugin(t, second, "app-hello", "")
+	writePlugin(t, second, "app-world", "")
+	writePlugin(t, second, "other-tool", "")
+	if err := os.WriteFile(filepath.Join(second, "app-data"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", second)
+
+	plugins := DiscoverPlugins("app-", first)
+	if len(plugins) != 2 || plugins[0].Name != "hello" || plugins[1].Name != "world" {
+		t.Fatalf("unexpected plugins: %+v", plugins)
+	}
+	if plugins[0].Path != want {
+		t.Errorf("earlier directory should win, got %s", plugins[0].Path)
+	}
+}
+
+func TestAddPlugins_RunsPlugin(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "app-hello", `echo "args: $*"
+echo "parent=$GZH_PLUGIN_PARENT debug=$GZH_PLUGIN_FLAG_DEBUG config=$GZH_PLUGIN_FLAG_CONFIG no_color=$NO_COLOR"
+exit 3
+`)
+	writePlugin(t, dir, "app-builtin", "echo shadowed\n")
+
+	root, flags, buf := newPluginRoot(t, dir)
+	root.SetArgs([]string{"--debug", "hello", "a", "--config", "x.yaml", "--name", "b", "--", "--debug"})
+
+	if code := ExecuteWithCode(root); code != 3 {
+		t.Errorf("exit code %d, want 3", code)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"args: a --name b -- --debug",
+		"parent=app debug=true config=x.yaml no_color=\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Error:") {
+		t.Errorf("plugin failures should not be reported again:\n%s", got)
+	}
+	if !flags.Debug || flags.Config != "x.yaml" {
+		t.Errorf("global flags should be parsed by the root: %+v", flags)
+	}
+
+	root, _, buf = newPluginRoot(t, dir)
+	root.SetArgs([]string{"builtin"})
+	if code := ExecuteWithCode(root); code != ExitOK || strings.Contains(buf.String(), "shadowed") {
+		t.Errorf("built-in command should take precedence over plugins")
+	}
+}
+
+func TestAddPlugins_Help(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "app-hello", "")
+
+	root, _, buf := newPluginRoot(t, dir)
+	root.SetArgs([]string{"--help"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "Plugin Commands:") || !strings.Contains(got, "hello") {
+		t.Errorf("help should list plugins:\n%s", got)
+	}
+}
+
+func TestAddPlugins_Completion(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "app-hello", `if [ "$1" = "__complete" ]; then
+  shift
+  echo "status"
+  echo "stash"
+  echo ":4"
+fi
+`)
+
+	root, _, buf := newPluginRoot(t, dir)
+	root.SetArgs([]string{cobra.ShellCompNoDescRequestCmd, "hello", "st"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, "status\nstash\n:4\n") {
+		t.Errorf("expected forwarded completions, got:\n%s", got)
+	}
+}
+
+func TestApplyPluginEnv(t *testing.T) {
+	t.Setenv("GZH_PLUGIN_FLAG_DEBUG", "true")
+	t.Setenv("GZH_PLUGIN_FLAG_CONFIG", "from-env.yaml")
+
+	root := &cobra.Command{Use: "app"}
+	var flags GlobalFlags
+	AddGlobalFlags(root, &flags)
+	if err := root.PersistentFlags().Parse([]string{"--config", "cli.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the marker, the binary was not started as a plugin.
+	if err := applyPluginEnv(root.PersistentFlags()); err != nil || flags.Debug {
+		t.Fatalf("expected the variables to be ignored, got %v, %+v", err, flags)
+	}
+
+	t.Setenv("GZH_PLUGIN_PARENT", "gz")
+	if err := applyPluginEnv(root.PersistentFlags()); err != nil {
+		t.Fatal(err)
+	}
+	if !flags.Debug || flags.Config != "cli.yaml" {
+		t.Errorf("expected env to fill unset flags only, got %+v", flags)
+	}
+
+	t.Setenv("GZH_PLUGIN_FLAG_QUIET", "maybe")
+	if err := applyPluginEnv(root.PersistentFlags()); err == nil {
+		t.Error("expected error for invalid value")
+	}
+}
+
+func TestAddPlugins_CanceledGracefully(t *testing.T) {
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	writePlugin(t, dir, "app-slow", `trap 'echo stopping; exit 7' INT
+touch "`+ready+`"
+while :; do sleep 0.05; done
+`)
+	root, _, buf := newPluginRoot(t, dir)
+	t.Setenv("PATH", "/bin:/usr/bin")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			if _, err := os.Stat(ready); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+	}()
+	root.SetArgs([]string{"slow"})
+	err := root.ExecuteContext(ctx)
+
+	var exitErr *pluginExitError
+	if !errors.As(err, &exitErr) || exitErr.code != 7 || !strings.Contains(buf.String(), "stopping") {
+		t.Errorf("expected the plugin to handle the interrupt, got %v:\n%s", err, buf.String())
+	}
+}
+
+func TestStopPlugin_TerminalInterrupt(t *testing.T) {
+	// The terminal has already interrupted the plugin, so nothing is sent;
+	// a nil process would panic if it were.
+	if err := stopPlugin(nil, &signalError{sig: os.Interrupt}); err != nil {
+		t.Errorf("stopPlugin = %v", err)
+	}
+}
+
+func TestParsePersistentFlags_Shorthands(t *testing.T) {
+	root := &cobra.Command{Use: "app"}
+	var flags GlobalFlags
+	AddGlobalFlags(root, &flags)
+
+	args := []string{"-cfile.yaml", "run", "-vn", "--debug=true", "-x", "--", "-q"}
+	rest, err := parsePersistentFlags(root.PersistentFlags(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags.Config != "file.yaml" || !flags.Debug || flags.Verbose || flags.Quiet {
+		t.Errorf("unexpected flags: %+v", flags)
+	}
+	if got := strings.Join(rest, " "); got != "run -vn -x -- -q" {
+		t.Errorf("unexpected rest %q", got)
+	}
+
+	flags = GlobalFlags{}
+	if _, err := parsePersistentFlags(root.PersistentFlags(), []string{"-vqc=x.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	if !flags.Verbose || !flags.Quiet || flags.Config != "x.yaml" {
+		t.Errorf("cluster not parsed: %+v", flags)
+	}
+}
@@ -0,0 +1,430 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// progressRefresh is how often spinners animate and progress redraws.
+	progressRefresh = 100 * time.Millisecond
+	// progressBarWidth is the widest a bar is drawn, in cells.
+	progressBarWidth    = 30
+	minProgressBarWidth = 10
+
+	// clearLine erases from the cursor to the end of the line.
+	clearLine = "\033[K"
+)
+
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerFramesASCII = []string{"|", "/", "-", "\\"}
+)
+
+// SetQuiet suppresses non-essential output, as with the --quiet flag:
+// progress indicators and Info messages. Results, errors and warnings are
+// still written.
+func (o *Output) SetQuiet(quiet bool) *Output {
+	o.quiet = quiet
+	return o
+}
+
+// progressEnabled reports whether progress indicators should be drawn.
+// They are written to the error writer, and only when it is a terminal,
+// output is not quiet and the format is not machine-readable data.
+func (o *Output) progressEnabled() bool {
+	if o.quiet || o.structured() || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(o.errWriter)
+}
+
+// ProgressBar shows the progress of a task with a known number of steps.
+// It is safe for concurrent use. When progress is disabled (see
+// Output.NewProgressBar) all methods are no-ops.
+type ProgressBar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	enabled  bool
+	unicode  bool
+	width    int
+	label    string
+	current  int
+	total    int
+	lastDraw time.Time
+}
+
+// NewProgressBar creates a progress bar for total steps on the error writer.
+// It draws nothing unless the error writer is a terminal, output is not
+// quiet and the format is not json, ndjson, yaml or llm.
+func (o *Output) NewProgressBar(label string, total int) *ProgressBar {
+	return &ProgressBar{
+		w:       o.errWriter,
+		enabled: o.progressEnabled(),
+		unicode: unicodeSupported(),
+		width:   terminalWidth(o.errWriter),
+		label:   label,
+		total:   total,
+	}
+}
+
+// Add advances the bar by n steps.
+func (b *ProgressBar) Add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current += n
+	b.draw(false)
+}
+
+// Set moves the bar to step n.
+func (b *ProgressBar) Set(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = n
+	b.draw(false)
+}
+
+// SetLabel changes the text shown before the bar.
+func (b *ProgressBar) SetLabel(label string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.label = label
+	b.draw(false)
+}
+
+// Finish draws the final state and ends the line.
+func (b *ProgressBar) Finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.draw(true) {
+		fmt.Fprintln(b.w)
+	}
+	b.enabled = false
+}
+
+// draw redraws the bar, at most every progressRefresh unless forced or
+// complete. It reports whether anything was drawn.
+func (b *ProgressBar) draw(force bool) bool {
+	if !b.enabled {
+		return false
+	}
+	now := time.Now()
+	if !force && b.current < b.total && now.Sub(b.lastDraw) < progressRefresh {
+		return false
+	}
+	b.lastDraw = now
+	fmt.Fprintf(b.w, "\r%s%s", renderProgress(b.label, b.current, b.total, b.width, b.unicode), clearLine)
+	return true
+}
+
+// Spinner shows that a task of unknown length is running. It animates in
+// the background between Start and Stop and is safe for concurrent use.
+type Spinner struct {
+	mu      sync.Mutex
+	w       io.Writer
+	enabled bool
+	frames  []string
+	label   string
+	started time.Time
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner creates a spinner on the error writer. Like progress bars, it
+// draws nothing when progress is disabled.
+func (o *Output) NewSpinner(label string) *Spinner {
+	frames := spinnerFrames
+	if !unicodeSupported() {
+		frames = spinnerFramesASCII
+	}
+	return &Spinner{
+		w:       o.errWriter,
+		enabled: o.progressEnabled(),
+		frames:  frames,
+		label:   label,
+	}
+}
+
+// Start begins animating the spinner.
+func (s *Spinner) Start() *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled || s.stop != nil {
+		return s
+	}
+	s.started = time.Now()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+	return s
+}
+
+// SetLabel changes the text shown next to the spinner.
+func (s *Spinner) SetLabel(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+}
+
+// Stop stops the animation and clears the spinner line, leaving the
+// terminal ready for a final message.
+func (s *Spinner) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop = nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	fmt.Fprint(s.w, "\r"+clearLine)
+}
+
+func (s *Spinner) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(progressRefresh)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		s.mu.Lock()
+		elapsed := time.Since(s.started).Truncate(time.Second)
+		fmt.Fprintf(s.w, "\r%s %s (%s)%s", s.frames[frame%len(s.frames)], s.label, elapsed, clearLine)
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// MultiProgress shows one line per task for concurrent work, e.g. cloning
+// many repositories at once. Lines are redrawn in the background until Stop.
+// It is safe for concurrent use.
+type MultiProgress struct {
+	mu      sync.Mutex
+	out     *Output
+	w       io.Writer
+	enabled bool
+	unicode bool
+	width   int
+	frames  []string
+	tasks   []*ProgressTask
+	lines   int
+	frame   int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// ProgressTask is one line of a MultiProgress.
+type ProgressTask struct {
+	m       *MultiProgress
+	label   string
+	status  string
+	current int
+	total   int
+	state   taskState
+}
+
+type taskState int
+
+const (
+	taskRunning taskState = iota
+	taskDone
+	taskFailed
+)
+
+// NewMultiProgress creates per-task progress on the error writer and starts
+// redrawing it. Like progress bars, it draws nothing when progress is
+// disabled. Call Stop when all tasks are finished.
+func (o *Output) NewMultiProgress() *MultiProgress {
+	frames := spinnerFrames
+	if !unicodeSupported() {
+		frames = spinnerFramesASCII
+	}
+	m := &MultiProgress{
+		out:     o,
+		w:       o.errWriter,
+		enabled: o.progressEnabled(),
+		unicode: unicodeSupported(),
+		width:   terminalWidth(o.errWriter),
+		frames:  frames,
+	}
+	if m.enabled {
+		m.stop = make(chan struct{})
+		m.done = make(chan struct{})
+		go m.run(m.stop, m.done)
+	}
+	return m
+}
+
+// AddTask adds a line for a task with total steps. A total of zero shows a
+// spinner instead of a bar.
+func (m *MultiProgress) AddTask(label string, total int) *ProgressTask {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	task := &ProgressTask{m: m, label: label, total: total}
+	m.tasks = append(m.tasks, task)
+	return task
+}
+
+// Stop draws the final state of every task and stops redrawing.
+func (m *MultiProgress) Stop() {
+	m.mu.Lock()
+	stop := m.stop
+	m.stop = nil
+	m.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-m.done
+	m.draw()
+}
+
+func (m *MultiProgress) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(progressRefresh)
+	defer ticker.Stop()
+	for {
+		m.draw()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw redraws all task lines in place.
+func (m *MultiProgress) draw() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labelWidth := 0
+	for _, task := range m.tasks {
+		if n := utf8.RuneCountInString(task.label); n > labelWidth {
+			labelWidth = n
+		}
+	}
+
+	var sb strings.Builder
+	if m.lines > 0 {
+		fmt.Fprintf(&sb, "\033[%dA", m.lines)
+	}
+	for _, task := range m.tasks {
+		sb.WriteString("\r" + m.renderTask(task, labelWidth) + clearLine + "\n")
+	}
+	m.lines = len(m.tasks)
+	m.frame++
+	_, _ = io.WriteString(m.w, sb.String())
+}
+
+func (m *MultiProgress) renderTask(t *ProgressTask, labelWidth int) string {
+	var icon string
+	switch t.state {
+	case taskDone:
+		icon = m.out.marker(m.w, markerSuccess)
+	case taskFailed:
+		icon = m.out.marker(m.w, markerError)
+	default:
+		icon = m.frames[m.frame%len(m.frames)]
+	}
+
+	label := t.label + strings.Repeat(" ", labelWidth-utf8.RuneCountInString(t.label))
+	text := label
+	if t.total > 0 && t.state == taskRunning {
+		text = renderProgress(label, t.current, t.total, m.width-2, m.unicode)
+	}
+	if t.status != "" {
+		text += "  " + t.status
+	}
+	// A line wrapping past the terminal width would throw off the cursor
+	// movement of the next redraw. The icon and its space take two columns,
+	// and the last column is left free.
+	if m.width > 0 {
+		text = truncate(text, max(m.width-3, 1))
+	}
+	return icon + " " + text
+}
+
+// Add advances the task by n steps.
+func (t *ProgressTask) Add(n int) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	t.current += n
+}
+
+// Set moves the task to step n.
+func (t *ProgressTask) Set(n int) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	t.current = n
+}
+
+// SetStatus sets a short status shown after the task, e.g. "cloning".
+func (t *ProgressTask) SetStatus(status string) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	t.status = status
+}
+
+// Done marks the task as finished successfully.
+func (t *ProgressTask) Done() {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	t.state = taskDone
+	t.current = t.total
+	t.status = ""
+}
+
+// Fail marks the task as failed and shows err as its status.
+func (t *ProgressTask) Fail(err error) {
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	t.state = taskFailed
+	if err != nil {
+		t.status = err.Error()
+	}
+}
+
+// renderProgress renders "label [=====     ] 5/10  50%", sizing the bar to
+// fit width (0 means no limit).
+func renderProgress(label string, current, total, width int, unicode bool) string {
+	if total <= 0 {
+		return fmt.Sprintf("%s %d", label, current)
+	}
+	if current > total {
+		current = total
+	}
+	if current < 0 {
+		current = 0
+	}
+
+	counts := fmt.Sprintf("%d/%d %3d%%", current, total, current*100/total)
+	cells := progressBarWidth
+	if width > 0 {
+		// label, spaces, brackets and counts around the bar
+		if room := width - utf8.RuneCountInString(label) - len(counts) - 4; room < cells {
+			cells = room
+		}
+		if cells < minProgressBarWidth {
+			cells = minProgressBarWidth
+		}
+	}
+
+	filled := cells * current / total
+	var bar string
+	if unicode {
+		bar = strings.Repeat("█", filled) + strings.Repeat("░", cells-filled)
+	} else {
+		bar = "[" + strings.Repeat("=", filled) + strings.Repeat(" ", cells-filled) + "]"
+	}
+	return label + " " + bar + " " + counts
+}
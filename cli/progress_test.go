@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func TestProgress_DisabledForNonTTY(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf)
+
+	bar := out.NewProgressBar("cloning", 3)
+	bar.Add(1)
+	bar.Finish()
+
+	spinner := out.NewSpinner("fetching").Start()
+	spinner.Stop()
+
+	multi := out.NewMultiProgress()
+	multi.AddTask("repo-a", 0).Done()
+	multi.Stop()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no progress output for a non-terminal writer, got %q", buf.String())
+	}
+}
+
+func TestProgress_DisabledForQuietAndData(t *testing.T) {
+	for _, out := range []*Output{
+		NewOutput().SetQuiet(true),
+		NewOutput().SetFormat("json"),
+		NewOutput().SetFormat("llm"),
+	} {
+		if out.progressEnabled() {
+			t.Errorf("progress should be disabled for quiet=%v format=%s", out.quiet, out.format)
+		}
+	}
+}
+
+func TestProgressBar_Draw(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewOutput().SetWriter(&buf).NewProgressBar("cloning", 4)
+	bar.enabled, bar.unicode, bar.width = true, false, 0
+
+	bar.Set(2)
+	bar.Finish()
+
+	if !strings.Contains(buf.String(), "\rcloning [===============               ] 2/4  50%") {
+		t.Errorf("unexpected progress output: %q", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), clearLine+"\n") {
+		t.Errorf("Finish should end the line, got %q", buf.String())
+	}
+}
+
+func TestMultiProgress_Draw(t *testing.T) {
+	var buf bytes.Buffer
+	multi := NewOutput().SetWriter(&buf).SetColorMode(ColorNever).NewMultiProgress()
+	multi.enabled, multi.unicode = true, false
+
+	a := multi.AddTask("repo-a", 0)
+	b := multi.AddTask("repo-bb", 0)
+	a.SetStatus("cloning")
+	b.Fail(errors.New("auth failed"))
+	multi.draw()
+	a.Done()
+	multi.draw()
+
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.HasSuffix(lines[0], "repo-a   cloning"+clearLine) {
+		t.Errorf("unexpected first line: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "repo-bb  auth failed") {
+		t.Errorf("unexpected failed task line: %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "\033[2A\r") {
+		t.Errorf("redraw should move the cursor up, got %q", lines[2])
+	}
+}
+
+func TestMultiProgress_TruncatesToWidth(t *testing.T) {
+	var buf bytes.Buffer
+	multi := NewOutput().SetWriter(&buf).SetColorMode(ColorNever).NewMultiProgress()
+	multi.enabled, multi.unicode, multi.width = true, false, 20
+
+	multi.AddTask("a-very-long-repository-name", 10).Add(5)
+	multi.AddTask("short", 0).SetStatus("waiting for the remote to answer")
+	multi.draw()
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		line = strings.TrimSuffix(strings.TrimPrefix(line, "\r"), clearLine)
+		if n := utf8.RuneCountInString(line); n >= multi.width {
+			t.Errorf("line of %d columns would wrap at %d: %q", n, multi.width, line)
+		}
+	}
+}
+
+func TestOutput_QuietSuppressesInfo(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetQuiet(true)
+	out.Info("cloning")
+	out.Warning("slow")
+
+	if strings.Contains(buf.String(), "cloning") || !strings.Contains(buf.String(), "slow") {
+		t.Errorf("quiet should suppress info but not warnings, got %q", buf.String())
+	}
+}
@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// ErrNonInteractive is returned by prompts that need an answer when stdin
+// is not a terminal. The returned errors also match errors.ErrUsage, since
+// the fix is to pass --yes (or the value as a flag).
+var ErrNonInteractive = errors.New("stdin is not a terminal")
+
+// Prompter asks the user questions. Prompts are written to stderr so that
+// stdout stays clean for data.
+//
+// With assume-yes set (--yes or --force), prompts return their default
+// without asking. Otherwise a prompt fails with ErrNonInteractive unless
+// stdin is a terminal or an input reader was injected with SetInput.
+type Prompter struct {
+	in        io.Reader
+	reader    *bufio.Reader
+	out       io.Writer
+	assumeYes bool
+	answered  bool // a line has been read from in
+
+	ctx     context.Context
+	pending chan readResult
+}
+
+// readResult is a line read from the input.
+type readResult struct {
+	line string
+	err  error
+}
+
+// NewPrompter creates a Prompter reading from stdin and writing to stderr.
+func NewPrompter() *Prompter {
+	return &Prompter{
+		in:  os.Stdin,
+		out: os.Stderr,
+	}
+}
+
+// NewPrompterFromFlags creates a Prompter that assumes yes when --yes or
+// --force was given. Either flag group may be nil.
+func NewPrompterFromFlags(confirm *ConfirmFlags, dryRun *DryRunFlags) *Prompter {
+	return NewPrompter().SetAssumeYes((confirm != nil && confirm.Yes) || (dryRun != nil && dryRun.Force))
+}
+
+// SetInput sets the reader answers are read from. Readers other than a
+// non-terminal *os.File are treated as interactive, so tests can script
+// answers with strings.NewReader.
+func (p *Prompter) SetInput(r io.Reader) *Prompter {
+	p.in = r
+	p.reader = nil
+	return p
+}
+
+// SetOutput sets the writer prompts are written to (stderr by default).
+func (p *Prompter) SetOutput(w io.Writer) *Prompter {
+	p.out = w
+	return p
+}
+
+// SetContext makes prompts return ctx.Err() as soon as ctx is done, e.g.
+// when ExecuteWithCode cancels cmd.Context() on Ctrl-C, instead of waiting
+// for the line being typed.
+func (p *Prompter) SetContext(ctx context.Context) *Prompter {
+	p.ctx = ctx
+	return p
+}
+
+// SetAssumeYes makes prompts return their defaults without asking.
+func (p *Prompter) SetAssumeYes(yes bool) *Prompter {
+	p.assumeYes = yes
+	return p
+}
+
+// Confirm asks a yes/no question. An empty answer returns def.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	if p.assumeYes {
+		return true, nil
+	}
+	if err := p.checkInteractive(question); err != nil {
+		return false, err
+	}
+
+	choices := "y/N"
+	if def {
+		choices = "Y/n"
+	}
+	for {
+		answer, err := p.ask(question, fmt.Sprintf("%s [%s]: ", question, choices))
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer y or n.")
+	}
+}
+
+// Select asks the user to pick one of options and returns its index.
+// An empty answer returns def; a negative def requires an answer and makes
+// the prompt fail under assume-yes.
+func (p *Prompter) Select(question string, options []string, def int) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("select %q: no options", question)
+	}
+	hasDefault := def >= 0 && def < len(options)
+	if p.assumeYes {
+		if !hasDefault {
+			return -1, errNoDefault(question)
+		}
+		return def, nil
+	}
+	if err := p.checkInteractive(question); err != nil {
+		return -1, err
+	}
+
+	p.printOptions(question, options)
+	label := "Choice: "
+	if hasDefault {
+		label = fmt.Sprintf("Choice [%d]: ", def+1)
+	}
+	for {
+		answer, err := p.ask(question, label)
+		if err != nil {
+			return -1, err
+		}
+		if answer == "" && hasDefault {
+			return def, nil
+		}
+		if i, ok := parseOption(answer, options); ok {
+			return i, nil
+		}
+		fmt.Fprintf(p.out, "Please enter a number between 1 and %d.\n", len(options))
+	}
+}
+
+// MultiSelect asks the user to pick any number of options, entered as
+// comma-separated numbers, and returns their indexes in the order given.
+// An empty answer returns defaults; "none" selects nothing.
+func (p *Prompter) MultiSelect(question string, options []string, defaults []int) ([]int, error) {
+	if p.assumeYes {
+		return defaults, nil
+	}
+	if err := p.checkInteractive(question); err != nil {
+		return nil, err
+	}
+
+	p.printOptions(question, options)
+	label := "Choices (comma-separated): "
+	if len(defaults) > 0 {
+		nums := make([]string, len(defaults))
+		for i, d := range defaults {
+			nums[i] = strconv.Itoa(d + 1)
+		}
+		label = fmt.Sprintf("Choices (comma-separated) [%s]: ", strings.Join(nums, ","))
+	}
+	for {
+		answer, err := p.ask(question, label)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return defaults, nil
+		case "none":
+			return []int{}, nil
+		}
+		if selected, ok := parseOptions(answer, options); ok {
+			return selected, nil
+		}
+		fmt.Fprintf(p.out, "Please enter numbers between 1 and %d, separated by commas.\n", len(options))
+	}
+}
+
+// Text asks for a line of text. An empty answer returns def; with an empty
+// def an answer is required and the prompt fails under assume-yes.
+func (p *Prompter) Text(question, def string) (string, error) {
+	if p.assumeYes {
+		if def == "" {
+			return "", errNoDefault(question)
+		}
+		return def, nil
+	}
+	if err := p.checkInteractive(question); err != nil {
+		return "", err
+	}
+
+	label := question + ": "
+	if def != "" {
+		label = fmt.Sprintf("%s [%s]: ", question, def)
+	}
+	for {
+		answer, err := p.ask(question, label)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if answer != "" {
+			return answer, nil
+		}
+	}
+}
+
+// Password asks for a secret without echoing it on the terminal. Secrets
+// have no default, so Password always asks, even under assume-yes. Echo is
+// restored when the prompt returns, including when its context is canceled,
+// and when a second interrupt forces the process to exit.
+func (p *Prompter) Password(question string) (string, error) {
+	if err := p.checkInteractive(question); err != nil {
+		return "", err
+	}
+
+	if f, ok := p.in.(*os.File); ok && isTerminal(f) {
+		restore, err := disableEcho(f.Fd())
+		if err != nil {
+			return "", fmt.Errorf("password prompt: %w", err)
+		}
+		unregister := onForceExit(restore)
+		defer func() {
+			unregister()
+			restore()
+			fmt.Fprintln(p.out)
+		}()
+	}
+	return p.ask(question, question+": ")
+}
+
+// checkInteractive returns an error if the prompt cannot be answered.
+func (p *Prompter) checkInteractive(question string) error {
+	if f, ok := p.in.(*os.File); ok && !isTerminal(f) {
+		return errNonInteractive(question)
+	}
+	return nil
+}
+
+// errNonInteractive returns the error for a prompt that cannot be answered.
+func errNonInteractive(question string) error {
+	return errors.Usage(fmt.Errorf("cannot prompt %q: %w (use --yes to accept defaults)", question, ErrNonInteractive))
+}
+
+// errNoDefault returns the error for a prompt that cannot be assumed.
+func errNoDefault(question string) error {
+	return errors.Usage(fmt.Errorf("cannot prompt %q: an answer is required and there is no default", question))
+}
+
+// ask writes label and reads one line of input, without the line ending
+// and surrounding whitespace. Input that ends before the first answer is
+// not interactive, as when stdin is /dev/null.
+func (p *Prompter) ask(question, label string) (string, error) {
+	fmt.Fprint(p.out, label)
+	line, err := p.readLine()
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err == io.EOF {
+		if !p.answered {
+			return "", errNonInteractive(question)
+		}
+		return "", fmt.Errorf("no answer: %w", io.ErrUnexpectedEOF)
+	}
+	if err == nil {
+		p.answered = true
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readLine reads one line of input. With a context set, the read runs in
+// a goroutine so that canceling the context ends the prompt; a read left
+// pending is picked up by the next prompt.
+func (p *Prompter) readLine() (string, error) {
+	if p.reader == nil {
+		p.reader = bufio.NewReader(p.in)
+	}
+	if p.ctx == nil {
+		return p.reader.ReadString('\n')
+	}
+	if err := p.ctx.Err(); err != nil {
+		return "", err
+	}
+	if p.pending == nil {
+		pending := make(chan readResult, 1)
+		go func() {
+			line, err := p.reader.ReadString('\n')
+			pending <- readResult{line, err}
+		}()
+		p.pending = pending
+	}
+	select {
+	case r := <-p.pending:
+		p.pending = nil
+		return r.line, r.err
+	case <-p.ctx.Done():
+		return "", p.ctx.Err()
+	}
+}
+
+// printOptions writes the question and its numbered options.
+func (p *Prompter) printOptions(question string, options []string) {
+	fmt.Fprintln(p.out, question)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+}
+
+// parseOption parses a 1-based option number or an exact option name.
+func parseOption(answer string, options []string) (int, bool) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		return n - 1, n >= 1 && n <= len(options)
+	}
+	for i, option := range options {
+		if option == answer {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// parseOptions parses comma-separated option numbers or names.
+func parseOptions(answer string, options []string) ([]int, bool) {
+	var selected []int
+	for _, part := range splitList([]string{answer}) {
+		i, ok := parseOption(part, options)
+		if !ok {
+			return nil, false
+		}
+		selected = append(selected, i)
+	}
+	return selected, len(selected) > 0
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func newTestPrompter(input string) (*Prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewPrompter().SetInput(strings.NewReader(input)).SetOutput(&out), &out
+}
+
+func TestPrompter_Confirm(t *testing.T) {
+	p, out := newTestPrompter("maybe\ny\n\n")
+
+	ok, err := p.Confirm("Delete 3 repos?", false)
+	if err != nil || !ok {
+		t.Fatalf("Confirm() = %v, %v; want true", ok, err)
+	}
+	if !strings.Contains(out.String(), "Delete 3 repos? [y/N]: ") || !strings.Contains(out.String(), "Please answer y or n.") {
+		t.Errorf("unexpected prompt output: %q", out.String())
+	}
+
+	ok, err = p.Confirm("Continue?", true)
+	if err != nil || !ok {
+		t.Errorf("empty answer should return the default, got %v, %v", ok, err)
+	}
+
+	if _, err := p.Confirm("Again?", false); err == nil {
+		t.Error("expected an error at end of input")
+	}
+}
+
+func TestPrompter_Select(t *testing.T) {
+	p, out := newTestPrompter("7\ndevelop\n\n")
+	options := []string{"main", "develop", "release"}
+
+	i, err := p.Select("Pick a branch:", options, 0)
+	if err != nil || i != 1 {
+		t.Fatalf("Select() = %d, %v; want 1", i, err)
+	}
+	if !strings.Contains(out.String(), "  2) develop\n") || !strings.Contains(out.String(), "Choice [1]: ") {
+		t.Errorf("unexpected prompt output: %q", out.String())
+	}
+
+	if i, _ := p.Select("Pick a branch:", options, 2); i != 2 {
+		t.Errorf("empty answer should return the default, got %d", i)
+	}
+}
+
+func TestPrompter_MultiSelectAndText(t *testing.T) {
+	p, _ := newTestPrompter("3, 1\n\nnone\n\nbob\n")
+	options := []string{"a", "b", "c"}
+
+	selected, err := p.MultiSelect("Repos:", options, []int{1})
+	if err != nil || len(selected) != 2 || selected[0] != 2 || selected[1] != 0 {
+		t.Fatalf("MultiSelect() = %v, %v; want [2 0]", selected, err)
+	}
+	if selected, _ := p.MultiSelect("Repos:", options, []int{1}); len(selected) != 1 || selected[0] != 1 {
+		t.Errorf("empty answer should return the defaults, got %v", selected)
+	}
+	if selected, _ := p.MultiSelect("Repos:", options, []int{1}); len(selected) != 0 {
+		t.Errorf("none should select nothing, got %v", selected)
+	}
+
+	if name, _ := p.Text("Name", "alice"); name != "alice" {
+		t.Errorf("Text() = %q, want default", name)
+	}
+	if name, _ := p.Text("Name", "alice"); name != "bob" {
+		t.Errorf("Text() = %q, want bob", name)
+	}
+}
+
+func TestPrompter_AssumeYes(t *testing.T) {
+	p := NewPrompterFromFlags(&ConfirmFlags{Yes: true}, nil).SetInput(strings.NewReader(""))
+
+	if ok, err := p.Confirm("Delete?", false); err != nil || !ok {
+		t.Errorf("Confirm() = %v, %v; want true", ok, err)
+	}
+	if i, err := p.Select("Pick:", []string{"a", "b"}, 1); err != nil || i != 1 {
+		t.Errorf("Select() = %d, %v; want default", i, err)
+	}
+	if _, err := p.Select("Pick:", []string{"a", "b"}, -1); !errors.Is(err, errors.ErrUsage) {
+		t.Errorf("Select without default should fail, got %v", err)
+	}
+	if _, err := p.Text("Name", ""); err == nil {
+		t.Error("Text without default should fail")
+	}
+
+	if !NewPrompterFromFlags(nil, &DryRunFlags{Force: true}).assumeYes {
+		t.Error("--force should assume yes")
+	}
+}
+
+func TestPrompter_NonInteractive(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	p := NewPrompter().SetInput(f).SetOutput(&bytes.Buffer{})
+	_, err = p.Confirm("Delete?", false)
+	if !errors.Is(err, ErrNonInteractive) || !errors.Is(err, errors.ErrUsage) {
+		t.Errorf("expected ErrNonInteractive, got %v", err)
+	}
+	if _, err := p.Password("Token"); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("expected ErrNonInteractive for password, got %v", err)
+	}
+}
+
+func TestPrompter_DevNull(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var out bytes.Buffer
+	p := NewPrompter().SetInput(f).SetOutput(&out)
+	if _, err := p.Confirm("Delete?", false); !errors.Is(err, ErrNonInteractive) || ExitCode(err) != ExitUsage {
+		t.Errorf("expected ErrNonInteractive with the usage exit code, got %v", err)
+	}
+	if _, err := p.Select("Pick", []string{"a", "b"}, 0); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("expected ErrNonInteractive for select, got %v", err)
+	}
+
+	// Input that ends before the first answer is not interactive either.
+	p = NewPrompter().SetInput(strings.NewReader("")).SetOutput(&out)
+	if _, err := p.Confirm("Delete?", false); !errors.Is(err, ErrNonInteractive) || !errors.Is(err, errors.ErrUsage) {
+		t.Errorf("expected ErrNonInteractive on immediate EOF, got %v", err)
+	}
+}
+
+func TestPrompter_ContextCanceled(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	p := NewPrompter().SetInput(in).SetOutput(&out).SetContext(ctx)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Password("Token")
+		done <- err
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Password() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("prompt did not return after the context was canceled")
+	}
+
+	// A line typed after the first prompt gave up goes to the next one.
+	p.SetContext(context.Background())
+	go func() { _, _ = io.WriteString(w, "yes\n") }()
+	if ok, err := p.Confirm("Continue?", false); err != nil || !ok {
+		t.Errorf("Confirm() = %v, %v; want true", ok, err)
+	}
+}
+
+func TestOnForceExit(t *testing.T) {
+	ran := 0
+	unregister := onForceExit(func() { ran++ })
+	runExitHooks()
+	unregister()
+	runExitHooks()
+	if ran != 1 {
+		t.Errorf("hook ran %d times, want 1", ran)
+	}
+}
@@ -0,0 +1,557 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-core/llm"
+)
+
+// filterOperators lists supported filter operators, longest first so that
+// "!=" is matched before "=".
+var filterOperators = []string{"!=", ">=", "<=", "~=", "=", ">", "<"}
+
+// query holds field selection, filtering and sorting applied to data before
+// any formatter runs.
+type query struct {
+	fields  []string
+	filters []string
+	sortBy  string
+}
+
+// filterExpr is a parsed --filter expression.
+type filterExpr struct {
+	path  []string
+	op    string
+	value string
+}
+
+// sortKey is a parsed --sort-by key.
+type sortKey struct {
+	path []string
+	desc bool
+}
+
+// SetFields restricts structured output to the given fields, in order.
+// Names match Go field names, json/yaml tags or LLM labels, ignoring case
+// and separators, so "created_at", "CreatedAt" and "CREATED_AT" are equal.
+func (o *Output) SetFields(fields ...string) *Output {
+	o.query.fields = splitList(fields)
+	return o
+}
+
+// SetFilters keeps only items matching every expression. Expressions have the
+// form FIELD OP VALUE with OP one of =, !=, ~= (contains), >, >=, <, <=.
+// Nested fields use dots, e.g. "owner.login=gizzahub".
+func (o *Output) SetFilters(filters ...string) *Output {
+	o.query.filters = filters
+	return o
+}
+
+// SetSortBy sorts items by a comma-separated list of fields. Prefix a field
+// with "-" to sort descending, e.g. "status,-updated_at".
+func (o *Output) SetSortBy(sortBy string) *Output {
+	o.query.sortBy = sortBy
+	return o
+}
+
+// isZero reports whether the query does nothing.
+func (q query) isZero() bool {
+	return len(q.fields) == 0 && len(q.filters) == 0 && q.sortBy == ""
+}
+
+// apply filters, sorts and projects data. Filtering and sorting only apply to
+// slices; field selection also applies to a single struct or map.
+func (q query) apply(data interface{}) (interface{}, error) {
+	if q.isZero() || data == nil {
+		return data, nil
+	}
+
+	filters, err := parseFilters(q.filters)
+	if err != nil {
+		return nil, err
+	}
+	keys := parseSortKeys(q.sortBy)
+
+	v := indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return data, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		if err := checkPaths(elemType, filters, keys, q.fields); err != nil {
+			return nil, err
+		}
+
+		result := reflect.MakeSlice(reflect.SliceOf(elemType), 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if matchesFilters(v.Index(i), filters) {
+				result = reflect.Append(result, v.Index(i))
+			}
+		}
+
+		if len(keys) > 0 {
+			sortSlice(result, keys)
+		}
+
+		if len(q.fields) > 0 {
+			return projectSlice(result, q.fields), nil
+		}
+		return result.Interface(), nil
+	case reflect.Struct, reflect.Map:
+		if len(q.fields) == 0 {
+			return data, nil
+		}
+		if err := checkPaths(v.Type(), nil, nil, q.fields); err != nil {
+			return nil, err
+		}
+		return project(v, q.fields).Interface(), nil
+	default:
+		return data, nil
+	}
+}
+
+// applyItem filters and projects a single streamed item. Sorting is not
+// applied since it requires the full data set. Returns false if the item is
+// filtered out.
+func (q query) applyItem(item interface{}) (interface{}, bool, error) {
+	if q.isZero() || item == nil {
+		return item, true, nil
+	}
+
+	filters, err := parseFilters(q.filters)
+	if err != nil {
+		return nil, false, err
+	}
+
+	v := reflect.ValueOf(item)
+	if err := checkPaths(v.Type(), filters, nil, q.fields); err != nil {
+		return nil, false, err
+	}
+	if !matchesFilters(v, filters) {
+		return nil, false, nil
+	}
+
+	if len(q.fields) > 0 {
+		if elem := indirect(v); elem.IsValid() && (elem.Kind() == reflect.Struct || elem.Kind() == reflect.Map) {
+			return project(elem, q.fields).Interface(), true, nil
+		}
+	}
+	return item, true, nil
+}
+
+// parseFilters parses --filter expressions.
+func parseFilters(exprs []string) ([]filterExpr, error) {
+	filters := make([]filterExpr, 0, len(exprs))
+	for _, expr := range exprs {
+		parsed, ok := parseFilter(expr)
+		if !ok {
+			return nil, fmt.Errorf("invalid filter %q: expected FIELD=VALUE (operators: %s)", expr, strings.Join(filterOperators, " "))
+		}
+		filters = append(filters, parsed)
+	}
+	return filters, nil
+}
+
+// parseFilter parses a single filter expression at its first operator.
+func parseFilter(expr string) (filterExpr, bool) {
+	best, bestOp := -1, ""
+	for _, op := range filterOperators {
+		if i := strings.Index(expr, op); i > 0 && (best < 0 || i < best) {
+			best, bestOp = i, op
+		}
+	}
+	if best < 0 {
+		return filterExpr{}, false
+	}
+	field := strings.TrimSpace(expr[:best])
+	if field == "" {
+		return filterExpr{}, false
+	}
+	return filterExpr{
+		path:  strings.Split(field, "."),
+		op:    bestOp,
+		value: strings.TrimSpace(expr[best+len(bestOp):]),
+	}, true
+}
+
+// parseSortKeys parses a --sort-by value.
+func parseSortKeys(sortBy string) []sortKey {
+	var keys []sortKey
+	for _, part := range strings.Split(sortBy, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := sortKey{}
+		if strings.HasPrefix(part, "-") {
+			key.desc = true
+			part = part[1:]
+		}
+		key.path = strings.Split(part, ".")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// checkPaths validates field names against a struct element type so typos
+// are reported instead of silently matching nothing. Map elements are not
+// checked since their keys vary per item.
+func checkPaths(elemType reflect.Type, filters []filterExpr, keys []sortKey, fields []string) error {
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	check := func(path []string) error {
+		t := elemType
+		for _, name := range path {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				return nil
+			}
+			field, ok := findField(t, name)
+			if !ok {
+				return fmt.Errorf("unknown field %q", strings.Join(path, "."))
+			}
+			t = field.Type
+		}
+		return nil
+	}
+
+	for _, f := range filters {
+		if err := check(f.path); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		if err := check(k.path); err != nil {
+			return err
+		}
+	}
+	for _, name := range fields {
+		if err := check([]string{name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findField finds an exported (possibly promoted) field by name.
+func findField(t reflect.Type, name string) (reflect.StructField, bool) {
+	want := normalizeFieldName(name)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		for _, candidate := range fieldNames(field) {
+			if normalizeFieldName(candidate) == want {
+				return field, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// fieldNames returns every name a field can be referred to by: its Go name,
+// LLM label and json/yaml tag names.
+func fieldNames(field reflect.StructField) []string {
+	names := []string{field.Name, llm.Label(field.Name), llm.FieldLabel(field)}
+	for _, key := range []string{"json", "yaml"} {
+		if tag := strings.Split(field.Tag.Get(key), ",")[0]; tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// normalizeFieldName lowercases name and strips separators.
+func normalizeFieldName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r == '_' || r == '-' || r == ' ' {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// lookupPath resolves a dotted field path on a struct or map value.
+func lookupPath(v reflect.Value, path []string) reflect.Value {
+	for _, name := range path {
+		v = indirect(v)
+		if !v.IsValid() {
+			return reflect.Value{}
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := findField(v.Type(), name)
+			if !ok {
+				return reflect.Value{}
+			}
+			v = fieldByIndex(v, field.Index)
+		case reflect.Map:
+			v = mapLookup(v, name)
+		default:
+			return reflect.Value{}
+		}
+	}
+	return v
+}
+
+// mapLookup finds a map entry whose key matches name.
+func mapLookup(m reflect.Value, name string) reflect.Value {
+	want := normalizeFieldName(name)
+	iter := m.MapRange()
+	for iter.Next() {
+		if normalizeFieldName(fmt.Sprint(iter.Key().Interface())) == want {
+			return iter.Value()
+		}
+	}
+	return reflect.Value{}
+}
+
+// matchesFilters reports whether item satisfies every filter.
+func matchesFilters(item reflect.Value, filters []filterExpr) bool {
+	for _, f := range filters {
+		if !f.matches(lookupPath(item, f.path)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches evaluates the filter against a field value.
+func (f filterExpr) matches(v reflect.Value) bool {
+	actual := valueString(v, ",")
+
+	switch f.op {
+	case "=":
+		return actual == f.value
+	case "!=":
+		return actual != f.value
+	case "~=":
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(f.value))
+	}
+
+	a, errA := strconv.ParseFloat(actual, 64)
+	b, errB := strconv.ParseFloat(f.value, 64)
+	cmp := 0
+	if errA == nil && errB == nil {
+		cmp = compareFloats(a, b)
+	} else {
+		cmp = strings.Compare(actual, f.value)
+	}
+
+	switch f.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// sortSlice stably sorts a slice value in place by the given keys.
+func sortSlice(slice reflect.Value, keys []sortKey) {
+	// Sort elements of a copy, so that writing them back cannot overwrite
+	// elements not yet moved. Nil elements stay valid Values.
+	orig := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	reflect.Copy(orig, slice)
+	items := make([]reflect.Value, orig.Len())
+	for i := range items {
+		items[i] = orig.Index(i)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compareValues(lookupPath(items[i], key.path), lookupPath(items[j], key.path))
+			if cmp == 0 {
+				continue
+			}
+			if key.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	for i, item := range items {
+		slice.Index(i).Set(item)
+	}
+}
+
+// compareValues orders two field values. Numbers, times and booleans compare
+// by value; everything else compares by its string form. Missing values sort first.
+func compareValues(a, b reflect.Value) int {
+	a, b = indirect(a), indirect(b)
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+
+	if ta, ok := a.Interface().(time.Time); ok {
+		if tb, ok := b.Interface().(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if b.Kind() == a.Kind() {
+			return compareFloats(float64(a.Int()), float64(b.Int()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if b.Kind() == a.Kind() {
+			return compareFloats(float64(a.Uint()), float64(b.Uint()))
+		}
+	case reflect.Float32, reflect.Float64:
+		if b.Kind() == a.Kind() {
+			return compareFloats(a.Float(), b.Float())
+		}
+	case reflect.Bool:
+		if b.Kind() == reflect.Bool && a.Bool() != b.Bool() {
+			if a.Bool() {
+				return 1
+			}
+			return -1
+		}
+		return 0
+	}
+
+	return strings.Compare(valueString(a, ","), valueString(b, ","))
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// projectSlice returns a new slice holding only the selected fields of each item.
+func projectSlice(slice reflect.Value, fields []string) interface{} {
+	elemType := slice.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	if elemType.Kind() == reflect.Struct {
+		projected, index := projectedType(elemType, fields)
+		result := reflect.MakeSlice(reflect.SliceOf(projected), 0, slice.Len())
+		for i := 0; i < slice.Len(); i++ {
+			item := indirect(slice.Index(i))
+			if !item.IsValid() {
+				continue
+			}
+			result = reflect.Append(result, projectStruct(item, projected, index))
+		}
+		return result.Interface()
+	}
+
+	result := make([]interface{}, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		item := indirect(slice.Index(i))
+		if !item.IsValid() {
+			continue
+		}
+		result = append(result, project(item, fields).Interface())
+	}
+	return result
+}
+
+// project returns a copy of a struct or map holding only the selected fields.
+func project(v reflect.Value, fields []string) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		projected, index := projectedType(v.Type(), fields)
+		return projectStruct(v, projected, index)
+	case reflect.Map:
+		result := reflect.MakeMap(v.Type())
+		for _, name := range fields {
+			want := normalizeFieldName(name)
+			iter := v.MapRange()
+			for iter.Next() {
+				if normalizeFieldName(fmt.Sprint(iter.Key().Interface())) == want {
+					result.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// projectedType builds a struct type containing only the selected fields, in
+// the requested order, keeping their original tags so every formatter names
+// them exactly as it would on the source type. It also returns the index of
+// each selected field in t.
+func projectedType(t reflect.Type, fields []string) (reflect.Type, [][]int) {
+	seen := make(map[string]bool)
+	var structFields []reflect.StructField
+	var index [][]int
+	for _, name := range fields {
+		field, ok := findField(t, name)
+		if !ok || seen[field.Name] {
+			continue
+		}
+		seen[field.Name] = true
+		structFields = append(structFields, reflect.StructField{
+			Name: field.Name,
+			Type: field.Type,
+			Tag:  field.Tag,
+		})
+		index = append(index, field.Index)
+	}
+	return reflect.StructOf(structFields), index
+}
+
+// projectStruct copies the fields of v at index, as returned by
+// projectedType, into a value of type projected.
+func projectStruct(v reflect.Value, projected reflect.Type, index [][]int) reflect.Value {
+	result := reflect.New(projected).Elem()
+	for i := range index {
+		if src := fieldByIndex(v, index[i]); src.IsValid() && src.CanInterface() {
+			result.Field(i).Set(src)
+		}
+	}
+	return result
+}
+
+// splitList splits comma-separated entries and drops empty ones.
+func splitList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
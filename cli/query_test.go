@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryOwner struct {
+	Login string `json:"login"`
+}
+
+type queryRepo struct {
+	Name      string     `json:"name" yaml:"name"`
+	Status    string     `json:"status" yaml:"status"`
+	Stars     int        `json:"stars" yaml:"stars"`
+	Owner     queryOwner `json:"owner" yaml:"owner"`
+	UpdatedAt time.Time  `json:"updated_at" yaml:"updated_at"`
+}
+
+func queryRepos() []queryRepo {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	return []queryRepo{
+		{Name: "alpha", Status: "ok", Stars: 5, Owner: queryOwner{Login: "gizzahub"}, UpdatedAt: day(3)},
+		{Name: "beta", Status: "failed", Stars: 12, Owner: queryOwner{Login: "bot"}, UpdatedAt: day(1)},
+		{Name: "gamma", Status: "failed", Stars: 1, Owner: queryOwner{Login: "gizzahub"}, UpdatedAt: day(2)},
+	}
+}
+
+func TestQuery_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("json").SetFields("status", "NAME")
+	if err := out.Print(queryRepos()[:1]); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "[\n  {\n    \"status\": \"ok\",\n    \"name\": \"alpha\"\n  }\n]\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestQuery_FieldsTableAndLLM(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table").SetFields("name,updated_at")
+	if err := out.Print(queryRepos()[:1]); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "NAME    UPDATED AT\n") {
+		t.Errorf("unexpected table output: %q", buf.String())
+	}
+
+	buf.Reset()
+	out.SetFormat("llm").SetFields("UPDATED_AT")
+	if err := out.Print(queryRepos()[0]); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if buf.String() != "UPDATED_AT: 2025-01-03T00:00:00Z\n" {
+		t.Errorf("unexpected llm output: %q", buf.String())
+	}
+}
+
+func TestQuery_FilterAndSort(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("csv").
+		SetFields("name").
+		SetFilters("status=failed").
+		SetSortBy("-stars")
+	if err := out.Print(queryRepos()); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if buf.String() != "name\nbeta\ngamma\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestQuery_FilterOperators(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{"stars>4", "alpha,beta"},
+		{"stars<=5", "alpha,gamma"},
+		{"status!=failed", "alpha"},
+		{"owner.login=gizzahub", "alpha,gamma"},
+		{"name~=ET", "beta"},
+	}
+
+	for _, tt := range tests {
+		data, err := (query{filters: []string{tt.filter}, fields: []string{"name"}}).apply(queryRepos())
+		if err != nil {
+			t.Fatalf("apply(%q) failed: %v", tt.filter, err)
+		}
+		var names []string
+		for _, item := range toSlice(data) {
+			names = append(names, lookupPath(item, []string{"name"}).String())
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("filter %q: got %s, want %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_SortByTime(t *testing.T) {
+	data, err := (query{sortBy: "updated_at"}).apply(queryRepos())
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	repos := data.([]queryRepo)
+	if repos[0].Name != "beta" || repos[2].Name != "alpha" {
+		t.Errorf("unexpected order: %v, %v, %v", repos[0].Name, repos[1].Name, repos[2].Name)
+	}
+}
+
+func TestQuery_Maps(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "b", "status": "ok", "count": 2},
+		{"name": "a", "status": "ok", "count": 10},
+		{"name": "c", "status": "failed", "count": 1},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("csv").
+		SetFields("name", "count").
+		SetFilters("status=ok").
+		SetSortBy("count")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if buf.String() != "count,name\n2,b\n10,a\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestQuery_FieldsTagMatchesOtherGoName(t *testing.T) {
+	// The tag of F is the Go name of another field.
+	data := []struct {
+		E int    `json:"f"`
+		F string `json:"x"`
+	}{{E: 1, F: "one"}}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("json").SetFields("x")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	got := strings.Join(strings.Fields(buf.String()), "")
+	if got != `[{"x":"one"}]` {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestQuery_SortNilItems(t *testing.T) {
+	data := []interface{}{
+		map[string]interface{}{"name": "b"},
+		nil,
+		map[string]interface{}{"name": "a"},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("json").SetSortBy("name")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	got := strings.Join(strings.Fields(buf.String()), "")
+	if got != `[null,{"name":"a"},{"name":"b"}]` {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	out := NewOutput().SetWriter(&bytes.Buffer{}).SetFormat("json")
+
+	if err := out.SetFields("nope").Print(queryRepos()); err == nil || !strings.Contains(err.Error(), `unknown field "nope"`) {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+	if err := out.SetFields().SetFilters("status").Print(queryRepos()); err == nil || !strings.Contains(err.Error(), "invalid filter") {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+}
+
+func TestQuery_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("ndjson").SetFields("name").SetFilters("status=failed")
+	emitAll(t, out, toInterfaces(queryRepos())...)
+
+	if buf.String() != "{\"name\":\"beta\"}\n{\"name\":\"gamma\"}\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func toInterfaces(repos []queryRepo) []interface{} {
+	items := make([]interface{}, len(repos))
+	for i, r := range repos {
+		items[i] = r
+	}
+	return items
+}
+
+func toSlice(data interface{}) []reflect.Value {
+	v := reflect.ValueOf(data)
+	items := make([]reflect.Value, v.Len())
+	for i := range items {
+		items[i] = v.Index(i)
+	}
+	return items
+}
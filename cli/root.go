@@ -1,10 +1,13 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
 )
 
 // RootConfig holds configuration for creating a root command.
@@ -42,60 +45,74 @@ func NewRootCmd(cfg RootConfig) *cobra.Command {
 }
 
 // Execute runs the root command and handles errors.
+// See ExecuteWithCode for how errors are reported.
 func Execute(cmd *cobra.Command) {
-	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+	if code := ExecuteWithCode(cmd); code != 0 {
+		os.Exit(code)
 	}
 }
 
 // ExecuteWithCode runs the root command and returns the exit code.
+//
+// Errors are rendered in the format selected by the failing command's
+// --format flag (see Output.PrintError): a structured error object for json,
+// ndjson, yaml and llm, and cobra's usual "Error: ..." text and usage
+// otherwise. SilenceErrors and SilenceUsage are honored as usual.
+//
+// The exit code is chosen by ExitCode; invalid flags and arguments are
+// usage errors and exit with ExitUsage.
+//
+// The command runs under a context (cmd.Context()) that is canceled on
+// SIGINT or SIGTERM; a second signal exits immediately. Hooks registered
+// with RegisterCleanup run after the command returns, and an interrupted
+// command exits with ExitInterrupt.
 func ExecuteWithCode(cmd *cobra.Command) int {
-	if err := cmd.Execute(); err != nil {
-		return 1
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
 	}
-	return 0
-}
+	ctx, interrupted, stop := interruptContext(parent, cmd.ErrOrStderr())
+	defer stop()
 
-// AddVersionCmd adds a version subcommand with extended info.
-func AddVersionCmd(root *cobra.Command, info VersionInfo) {
-	versionCmd := &cobra.Command{
-		Use:   "version",
-		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println(info.String())
-		},
+	silenceErrors, silenceUsage := cmd.SilenceErrors, cmd.SilenceUsage
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	restore := markUsageErrors(cmd)
+	executed, err := cmd.ExecuteContextC(ctx)
+	restore()
+	cmd.SilenceErrors, cmd.SilenceUsage = silenceErrors, silenceUsage
+	runCleanups(cmd.ErrOrStderr())
+	if executed == nil {
+		executed = cmd
 	}
-	root.AddCommand(versionCmd)
-}
-
-// VersionInfo holds extended version information.
-type VersionInfo struct {
-	Version   string
-	GitCommit string
-	BuildDate string
-	GoVersion string
-	Platform  string
-}
 
-// String returns a formatted version string.
-func (v VersionInfo) String() string {
-	s := fmt.Sprintf("Version:    %s", v.Version)
-	if v.GitCommit != "" && v.GitCommit != "unknown" {
-		s += fmt.Sprintf("\nGit Commit: %s", v.GitCommit)
+	if interrupted() {
+		// Errors caused by the cancellation itself are expected.
+		if err != nil && !errors.Is(err, context.Canceled) && !silenceErrors && !executed.SilenceErrors {
+			_ = errorOutput(executed).PrintError(err)
+		}
+		return ExitInterrupt
 	}
-	if v.BuildDate != "" && v.BuildDate != "unknown" {
-		s += fmt.Sprintf("\nBuild Date: %s", v.BuildDate)
+	if err == nil {
+		return ExitOK
 	}
-	if v.GoVersion != "" {
-		s += fmt.Sprintf("\nGo Version: %s", v.GoVersion)
+	err = asUsageError(err)
+
+	out := errorOutput(executed)
+	if !silenceErrors && !executed.SilenceErrors {
+		_ = out.PrintError(err)
 	}
-	if v.Platform != "" {
-		s += fmt.Sprintf("\nPlatform:   %s", v.Platform)
+	if !silenceUsage && !executed.SilenceUsage && !out.structured() {
+		executed.Println(executed.UsageString())
 	}
-	return s
+	return ExitCode(err)
 }
 
-// Short returns just the version string.
-func (v VersionInfo) Short() string {
-	return v.Version
+// errorOutput returns an Output for reporting errors of cmd, using its
+// --format flag when present.
+func errorOutput(cmd *cobra.Command) *Output {
+	out := NewOutput().SetWriter(cmd.OutOrStdout()).SetErrorWriter(cmd.ErrOrStderr())
+	if f := cmd.Flags().Lookup("format"); f != nil && f.Value.String() != "" {
+		out.SetFormat(f.Value.String())
+	}
+	return out
 }
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DefaultCleanupTimeout is how long cleanup hooks may run in total.
+const DefaultCleanupTimeout = 5 * time.Second
+
+// interruptSignals cancel the command context.
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+var (
+	cleanupMu      sync.Mutex
+	cleanupHooks   []func(ctx context.Context) error
+	cleanupTimeout = DefaultCleanupTimeout
+
+	// forceExit ends the process on a second interrupt.
+	forceExit = os.Exit
+
+	exitHooksMu sync.Mutex
+	exitHooks   = map[int]func(){}
+	nextExitID  int
+)
+
+// RegisterCleanup registers a hook run by ExecuteWithCode after the command
+// returns, whether it succeeded, failed or was interrupted, e.g. to remove
+// partially written files. Hooks run once, in reverse order of
+// registration, and share a context that expires after the cleanup timeout.
+// Errors are reported as warnings.
+func RegisterCleanup(fn func(ctx context.Context) error) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanupHooks = append(cleanupHooks, fn)
+}
+
+// SetCleanupTimeout sets how long cleanup hooks may run in total before
+// ExecuteWithCode gives up on them (DefaultCleanupTimeout by default).
+func SetCleanupTimeout(d time.Duration) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+	cleanupTimeout = d
+}
+
+// interruptContext returns a context canceled on SIGINT or SIGTERM, with a
+// *signalError cause naming the signal (see context.Cause). After
+// the first signal a second one exits immediately with ExitInterrupt,
+// skipping cleanup. interrupted reports whether a signal was received;
+// stop releases the signal handler.
+func interruptContext(parent context.Context, errOut io.Writer) (ctx context.Context, interrupted func() bool, stop func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, interruptSignals...)
+
+	var got atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-done:
+			return
+		}
+		got.Store(true)
+		fmt.Fprintln(errOut, "Interrupted; cleaning up (interrupt again to force quit)")
+		cancel(&signalError{sig: sig})
+
+		select {
+		case <-signals:
+			runExitHooks()
+			forceExit(ExitInterrupt)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel(nil)
+		})
+	}
+	return ctx, got.Load, stop
+}
+
+// signalError is the cause of a context canceled by interruptContext.
+type signalError struct {
+	sig os.Signal
+}
+
+func (e *signalError) Error() string {
+	return "received " + e.sig.String()
+}
+
+// onForceExit registers fn to run before a second interrupt exits the
+// process, which skips deferred calls, e.g. to restore terminal echo. The
+// returned function unregisters it.
+func onForceExit(fn func()) (unregister func()) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	id := nextExitID
+	nextExitID++
+	exitHooks[id] = fn
+	return func() {
+		exitHooksMu.Lock()
+		defer exitHooksMu.Unlock()
+		delete(exitHooks, id)
+	}
+}
+
+// runExitHooks runs the hooks registered with onForceExit.
+func runExitHooks() {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	for _, fn := range exitHooks {
+		fn()
+	}
+}
+
+// runCleanups runs and clears the registered cleanup hooks, newest first.
+// It stops waiting when the cleanup timeout expires.
+func runCleanups(errOut io.Writer) {
+	cleanupMu.Lock()
+	hooks, timeout := cleanupHooks, cleanupTimeout
+	cleanupHooks = nil
+	cleanupMu.Unlock()
+	if len(hooks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if ctx.Err() != nil {
+				return
+			}
+			// After the timeout, the caller has stopped waiting and reports it.
+			if err := hooks[i](ctx); err != nil && ctx.Err() == nil {
+				fmt.Fprintf(errOut, "Warning: cleanup failed: %v\n", err)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		fmt.Fprintf(errOut, "Warning: cleanup did not finish within %s\n", timeout)
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// resetCleanups restores the cleanup hooks and timeout after a test.
+func resetCleanups(t *testing.T) {
+	t.Helper()
+	cleanupMu.Lock()
+	hooks, timeout := cleanupHooks, cleanupTimeout
+	cleanupMu.Unlock()
+	t.Cleanup(func() {
+		cleanupMu.Lock()
+		cleanupHooks, cleanupTimeout = hooks, timeout
+		cleanupMu.Unlock()
+	})
+}
+
+func TestExecuteWithCode_Interrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("cannot send SIGINT to self")
+	}
+	resetCleanups(t)
+
+	var order []string
+	RegisterCleanup(func(context.Context) error { order = append(order, "first"); return nil })
+	RegisterCleanup(func(context.Context) error { order = append(order, "second"); return nil })
+
+	root := &cobra.Command{
+		Use: "app",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, _ := os.FindProcess(os.Getpid())
+			if err := p.Signal(os.Interrupt); err != nil {
+				return err
+			}
+			select {
+			case <-cmd.Context().Done():
+				return cmd.Context().Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("context was not canceled")
+			}
+		},
+	}
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{})
+
+	if code := ExecuteWithCode(root); code != ExitInterrupt {
+		t.Errorf("exit code %d, want %d", code, ExitInterrupt)
+	}
+	if strings.Join(order, ",") != "second,first" {
+		t.Errorf("cleanup hooks should run in reverse order, got %v", order)
+	}
+	if got := buf.String(); !strings.Contains(got, "Interrupted") || strings.Contains(got, "Error:") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
+
+func TestRunCleanups(t *testing.T) {
+	resetCleanups(t)
+
+	ran := 0
+	RegisterCleanup(func(context.Context) error { ran++; return nil })
+	RegisterCleanup(func(context.Context) error { return errors.New("disk full") })
+
+	var buf bytes.Buffer
+	runCleanups(&buf)
+	if ran != 1 || !strings.Contains(buf.String(), "Warning: cleanup failed: disk full") {
+		t.Errorf("ran=%d output=%q", ran, buf.String())
+	}
+
+	buf.Reset()
+	runCleanups(&buf)
+	if ran != 1 || buf.Len() != 0 {
+		t.Error("hooks should run only once")
+	}
+}
+
+func TestRunCleanups_Timeout(t *testing.T) {
+	resetCleanups(t)
+	SetCleanupTimeout(20 * time.Millisecond)
+
+	RegisterCleanup(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var buf bytes.Buffer
+	start := time.Now()
+	runCleanups(&buf)
+	if time.Since(start) > time.Second {
+		t.Error("cleanup should give up after the timeout")
+	}
+	if !strings.Contains(buf.String(), "did not finish within 20ms") {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-core/llm"
+)
+
+// Stream writes items one at a time without holding the full data set in
+// memory. Create one with Output.Stream, call Begin, Emit each item, and End.
+//
+// The output depends on the configured format:
+//   - ndjson: one compact JSON object per line
+//   - json: a single, properly bracketed JSON array
+//   - yaml: one YAML document per item, separated by "---"
+//   - table, csv, tsv: rows flushed as they arrive; columns come from the first item
+//   - llm: numbered item blocks
+//   - text: one line per item
+//
+// Field selection and filters apply to each item; sorting is not applied
+// since it requires the full data set.
+type Stream struct {
+	out   *Output
+	w     io.Writer
+	count int
+	began bool
+	ended bool
+
+	yamlEnc *yaml.Encoder
+
+	tableColumns []tableColumn
+	tableWidths  []int
+
+	csvWriter  *csv.Writer
+	csvColumns []csvColumn
+}
+
+// Stream returns a streaming writer for the configured format.
+func (o *Output) Stream() *Stream {
+	return &Stream{out: o, w: o.dataWriter()}
+}
+
+// Begin writes any leading framing, such as the opening bracket of a JSON array.
+func (s *Stream) Begin() error {
+	if s.began {
+		return nil
+	}
+	s.began = true
+
+	switch s.out.format {
+	case "json":
+		_, err := io.WriteString(s.w, "[")
+		return err
+	case "yaml", "yml":
+		s.yamlEnc = yaml.NewEncoder(s.w)
+		s.yamlEnc.SetIndent(2)
+	}
+	return nil
+}
+
+// Emit writes a single item. Begin is called automatically if needed.
+func (s *Stream) Emit(item interface{}) error {
+	if s.ended {
+		return fmt.Errorf("stream already ended")
+	}
+	if err := s.Begin(); err != nil {
+		return err
+	}
+
+	item, keep, err := s.out.query.applyItem(item)
+	if err != nil || !keep {
+		return err
+	}
+	// Items that fail to write are not counted, so the JSON separator and
+	// llm numbering only account for items actually written.
+	if err := s.emit(item); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// emit writes item in the configured format.
+func (s *Stream) emit(item interface{}) error {
+	switch s.out.format {
+	case "ndjson", "jsonl":
+		return writeNDJSON(s.w, item)
+	case "json":
+		return s.emitJSON(item)
+	case "yaml", "yml":
+		return s.yamlEnc.Encode(item)
+	case "llm":
+		return s.emitLLM(item)
+	case "table":
+		return s.emitTable(item)
+	case "csv":
+		return s.emitDelimited(item, ',')
+	case "tsv":
+		return s.emitDelimited(item, '\t')
+	default:
+		_, err := fmt.Fprintln(s.w, item)
+		return err
+	}
+}
+
+// End writes any trailing framing. It is safe to call End more than once.
+func (s *Stream) End() error {
+	if s.ended {
+		return nil
+	}
+	if err := s.Begin(); err != nil {
+		return err
+	}
+	s.ended = true
+
+	switch s.out.format {
+	case "json":
+		if s.count == 0 {
+			_, err := io.WriteString(s.w, "]\n")
+			return err
+		}
+		_, err := io.WriteString(s.w, "\n]\n")
+		return err
+	case "yaml", "yml":
+		return s.yamlEnc.Close()
+	}
+	return nil
+}
+
+// Count returns the number of items emitted so far.
+func (s *Stream) Count() int {
+	return s.count
+}
+
+func (s *Stream) emitJSON(item interface{}) error {
+	data, err := json.MarshalIndent(item, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	sep := "\n  "
+	if s.count > 0 {
+		sep = ",\n  "
+	}
+	_, err = io.WriteString(s.w, sep+string(data))
+	return err
+}
+
+func (s *Stream) emitLLM(item interface{}) error {
+	formatter := llm.NewFormatter(s.out.llmOptions)
+	formatted := formatter.FormatAt(item, 1)
+	if formatted == "" {
+		return nil
+	}
+	if !strings.Contains(formatted, "\n") {
+		formatted = formatter.Indent(1) + formatted + "\n"
+	}
+	_, err := fmt.Fprintf(s.w, "[%d]\n%s", s.count, formatted)
+	return err
+}
+
+// emitTable writes one table row. Column widths grow as wider rows arrive
+// (never shrinking) and are capped to the terminal width.
+func (s *Stream) emitTable(item interface{}) error {
+	row := indirect(reflect.ValueOf(item))
+	if !row.IsValid() {
+		return nil
+	}
+
+	var lines [][]string
+	if s.tableColumns == nil {
+		s.tableColumns = tableColumns([]reflect.Value{row}, row.Type())
+		if !s.out.noHeader {
+			header := make([]string, len(s.tableColumns))
+			for i, col := range s.tableColumns {
+				header[i] = col.header
+			}
+			lines = append(lines, header)
+		}
+	}
+
+	cells := make([]string, len(s.tableColumns))
+	for i, col := range s.tableColumns {
+		cells[i] = cellString(col.value(row))
+	}
+	lines = append(lines, cells)
+
+	widths := columnWidths(lines, len(s.tableColumns))
+	if s.tableWidths == nil {
+		s.tableWidths = widths
+	} else {
+		for i, w := range widths {
+			if w > s.tableWidths[i] {
+				s.tableWidths[i] = w
+			}
+		}
+	}
+	fitColumnWidths(s.tableWidths, s.out.tableWidth())
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(formatTableLine(line, s.tableWidths))
+	}
+	_, err := io.WriteString(s.w, sb.String())
+	return err
+}
+
+// emitDelimited writes one csv/tsv record and flushes it.
+func (s *Stream) emitDelimited(item interface{}, comma rune) error {
+	row := indirect(reflect.ValueOf(item))
+	if !row.IsValid() {
+		return nil
+	}
+
+	if s.csvWriter == nil {
+		s.csvWriter = csv.NewWriter(s.w)
+		s.csvWriter.Comma = comma
+		s.csvColumns = csvColumns([]reflect.Value{row}, row.Type())
+		if !s.out.noHeader {
+			header := make([]string, len(s.csvColumns))
+			for i, col := range s.csvColumns {
+				header[i] = col.name
+			}
+			if err := s.csvWriter.Write(header); err != nil {
+				return err
+			}
+		}
+	}
+
+	record := make([]string, len(s.csvColumns))
+	for i, col := range s.csvColumns {
+		record[i] = valueString(col.value(row), csvListSeparator)
+	}
+	if err := s.csvWriter.Write(record); err != nil {
+		return err
+	}
+	s.csvWriter.Flush()
+	return s.csvWriter.Error()
+}
+
+// printNDJSON prints each element of a slice as one JSON line.
+// Non-slice data is written as a single line.
+func (o *Output) printNDJSON(data interface{}) error {
+	v := indirect(reflect.ValueOf(data))
+	if v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			if err := writeNDJSON(o.dataWriter(), v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return writeNDJSON(o.dataWriter(), data)
+}
+
+// writeNDJSON writes item as a single line of compact JSON.
+func writeNDJSON(w io.Writer, item interface{}) error {
+	return json.NewEncoder(w).Encode(item)
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type streamItem struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+func emitAll(t *testing.T, out *Output, items ...interface{}) {
+	t.Helper()
+	s := out.Stream()
+	if err := s.Begin(); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for _, item := range items {
+		if err := s.Emit(item); err != nil {
+			t.Fatalf("Emit failed: %v", err)
+		}
+	}
+	if err := s.End(); err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+}
+
+func TestStream_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("ndjson"),
+		streamItem{Name: "a", Count: 1}, streamItem{Name: "b", Count: 2})
+
+	want := "{\"name\":\"a\",\"count\":1}\n{\"name\":\"b\",\"count\":2}\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestStream_JSONArray(t *testing.T) {
+	var buf bytes.Buffer
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("json"),
+		streamItem{Name: "a", Count: 1}, streamItem{Name: "b", Count: 2})
+
+	var items []streamItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("expected valid JSON array, got %v: %s", err, buf.String())
+	}
+	if len(items) != 2 || items[1].Name != "b" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestStream_JSONSkipsFailedItems(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewOutput().SetWriter(&buf).SetFormat("json").Stream()
+	if err := s.Emit(map[string]interface{}{"bad": make(chan int)}); err == nil {
+		t.Fatal("expected an error for an unmarshalable item")
+	}
+	if err := s.Emit(streamItem{Name: "a", Count: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.End(); err != nil {
+		t.Fatal(err)
+	}
+
+	var items []streamItem
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+		t.Fatalf("expected valid JSON array, got %v: %s", err, buf.String())
+	}
+	if len(items) != 1 || s.Count() != 1 {
+		t.Errorf("unexpected items: %+v (count %d)", items, s.Count())
+	}
+}
+
+func TestStream_JSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("json"))
+
+	if buf.String() != "[]\n" {
+		t.Errorf("expected empty array, got %q", buf.String())
+	}
+}
+
+func TestStream_YAMLDocuments(t *testing.T) {
+	var buf bytes.Buffer
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("yaml"),
+		streamItem{Name: "a", Count: 1}, streamItem{Name: "b", Count: 2})
+
+	want := "name: a\ncount: 1\n---\nname: b\ncount: 2\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestStream_TableFlushesRows(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewOutput().SetWriter(&buf).SetFormat("table").Stream()
+
+	if err := s.Emit(streamItem{Name: "a", Count: 1}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if buf.String() != "NAME   COUNT\na      1\n" {
+		t.Errorf("expected header and first row to be flushed, got %q", buf.String())
+	}
+
+	if err := s.Emit(streamItem{Name: "longer", Count: 2}); err != nil {
+		t.Fatalf("Emit failed: %v", err)
+	}
+	if !strings.HasSuffix(buf.String(), "longer   2\n") {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+	if err := s.End(); err != nil {
+		t.Fatalf("End failed: %v", err)
+	}
+}
+
+func TestStream_CSVAndLLM(t *testing.T) {
+	var buf bytes.Buffer
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("csv"),
+		streamItem{Name: "a", Count: 1}, streamItem{Name: "b", Count: 2})
+	if buf.String() != "name,count\na,1\nb,2\n" {
+		t.Errorf("unexpected csv output: %q", buf.String())
+	}
+
+	buf.Reset()
+	emitAll(t, NewOutput().SetWriter(&buf).SetFormat("llm"), streamItem{Name: "a", Count: 1})
+	if buf.String() != "[0]\n  NAME: a\n  COUNT: 1\n" {
+		t.Errorf("unexpected llm output: %q", buf.String())
+	}
+}
+
+func TestStream_EmitAfterEnd(t *testing.T) {
+	s := NewOutput().SetWriter(&bytes.Buffer{}).SetFormat("ndjson").Stream()
+	_ = s.End()
+	if err := s.Emit("x"); err == nil {
+		t.Error("expected error when emitting after End")
+	}
+}
+
+func TestOutput_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("ndjson")
+	if err := out.Print([]streamItem{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 2 {
+		t.Errorf("expected one line per item, got %q", buf.String())
+	}
+}
@@ -0,0 +1,372 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gizzahub/gzh-cli-core/llm"
+)
+
+const (
+	tableColumnGap = 3
+	minColumnWidth = 5
+	ellipsis       = "…"
+)
+
+// tableColumn describes a single table column.
+type tableColumn struct {
+	header string
+	// value extracts the cell value from a row.
+	value func(row reflect.Value) reflect.Value
+}
+
+// printTable prints slices of structs or maps as an aligned table.
+// Columns are derived from exported field names or `table:"NAME"` struct tags;
+// fields tagged `table:"-"` are skipped.
+func (o *Output) printTable(data interface{}) error {
+	rows, elemType, ok := tableRows(data)
+	if !ok {
+		return o.printText(data)
+	}
+
+	columns := tableColumns(rows, elemType)
+	if len(columns) == 0 {
+		return nil
+	}
+
+	cells := make([][]string, 0, len(rows)+1)
+	if !o.noHeader {
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.header
+		}
+		cells = append(cells, header)
+	}
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, col := range columns {
+			line[i] = cellString(col.value(row))
+		}
+		cells = append(cells, line)
+	}
+
+	widths := columnWidths(cells, len(columns))
+	fitColumnWidths(widths, o.tableWidth())
+
+	var sb strings.Builder
+	for _, line := range cells {
+		sb.WriteString(formatTableLine(line, widths))
+	}
+	_, err := fmt.Fprint(o.dataWriter(), sb.String())
+	return err
+}
+
+// tableWidth returns the maximum table width, or 0 for no limit.
+func (o *Output) tableWidth() int {
+	if o.maxWidth > 0 {
+		return o.maxWidth
+	}
+	return terminalWidth(o.dataWriter())
+}
+
+// tableRows normalizes data into rows. A single struct or map becomes a
+// one-row table. Returns false if data cannot be rendered as a table.
+func tableRows(data interface{}) ([]reflect.Value, reflect.Type, bool) {
+	if data == nil {
+		return nil, nil, false
+	}
+
+	v := indirect(reflect.ValueOf(data))
+	if !v.IsValid() {
+		return nil, nil, false
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		if isScalarType(v.Type()) {
+			return nil, nil, false
+		}
+		return []reflect.Value{v}, v.Type(), true
+	case reflect.Slice, reflect.Array:
+		elemType := v.Type().Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		rows := make([]reflect.Value, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem := indirect(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			rows = append(rows, elem)
+		}
+		return rows, elemType, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// tableColumns derives columns from the row element type, falling back to
+// inspecting rows when the element type is an interface.
+func tableColumns(rows []reflect.Value, elemType reflect.Type) []tableColumn {
+	if elemType.Kind() == reflect.Interface && len(rows) > 0 {
+		elemType = rows[0].Type()
+	}
+
+	switch {
+	case elemType.Kind() == reflect.Struct && !isScalarType(elemType):
+		return structColumns(elemType, nil)
+	case elemType.Kind() == reflect.Map:
+		return mapColumns(rows)
+	default:
+		return []tableColumn{{
+			header: "VALUE",
+			value:  func(row reflect.Value) reflect.Value { return row },
+		}}
+	}
+}
+
+// structColumns returns columns for the exported fields of t.
+// Embedded structs have their fields promoted into the parent table.
+func structColumns(t reflect.Type, index []int) []tableColumn {
+	var columns []tableColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Anonymous && tag == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !isScalarType(ft) {
+				columns = append(columns, structColumns(ft, fieldIndex)...)
+				continue
+			}
+		}
+
+		header := tag
+		if header == "" {
+			header = strings.ReplaceAll(llm.Label(field.Name), "_", " ")
+		}
+
+		columns = append(columns, tableColumn{
+			header: header,
+			value: func(row reflect.Value) reflect.Value {
+				return fieldByIndex(row, fieldIndex)
+			},
+		})
+	}
+
+	return columns
+}
+
+// mapColumns returns one column per map key across all rows, sorted by key.
+func mapColumns(rows []reflect.Value) []tableColumn {
+	keys := mapKeys(rows)
+	columns := make([]tableColumn, 0, len(keys))
+	for _, key := range keys {
+		columns = append(columns, tableColumn{
+			header: strings.ToUpper(fmt.Sprint(key.Interface())),
+			value:  mapValue(key),
+		})
+	}
+	return columns
+}
+
+// mapKeys returns the distinct keys across map rows, sorted by their string form.
+func mapKeys(rows []reflect.Value) []reflect.Value {
+	seen := make(map[string]reflect.Value)
+	for _, row := range rows {
+		if row.Kind() != reflect.Map {
+			continue
+		}
+		iter := row.MapRange()
+		for iter.Next() {
+			name := fmt.Sprint(iter.Key().Interface())
+			if _, ok := seen[name]; !ok {
+				seen[name] = iter.Key()
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keys := make([]reflect.Value, len(names))
+	for i, name := range names {
+		keys[i] = seen[name]
+	}
+	return keys
+}
+
+// mapValue returns an extractor for key from a map row.
+func mapValue(key reflect.Value) func(row reflect.Value) reflect.Value {
+	return func(row reflect.Value) reflect.Value {
+		if row.Kind() != reflect.Map || !key.Type().AssignableTo(row.Type().Key()) {
+			return reflect.Value{}
+		}
+		return row.MapIndex(key)
+	}
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but returns an invalid
+// value instead of panicking on nil embedded pointers.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			v = indirect(v)
+			if !v.IsValid() {
+				return reflect.Value{}
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isScalarType reports whether t is a struct type rendered as a single value.
+func isScalarType(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{})
+}
+
+// cellString renders a value as a single-line table cell.
+func cellString(v reflect.Value) string {
+	return strings.Join(strings.Fields(valueString(v, ", ")), " ")
+}
+
+// valueString renders a value as plain text, joining slice elements with sep.
+func valueString(v reflect.Value, sep string) string {
+	v = indirect(v)
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+
+	switch iface := v.Interface().(type) {
+	case time.Time:
+		if iface.IsZero() {
+			return ""
+		}
+		return iface.Format(time.RFC3339)
+	case time.Duration:
+		return iface.String()
+	case []byte:
+		return fmt.Sprintf("%x", iface)
+	case fmt.Stringer:
+		return iface.String()
+	case error:
+		return iface.Error()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, valueString(v.Index(i), sep))
+		}
+		return strings.Join(items, sep)
+	case reflect.Map:
+		keys := v.MapKeys()
+		items := make([]string, 0, len(keys))
+		for _, key := range keys {
+			items = append(items, fmt.Sprintf("%v=%s", key.Interface(), valueString(v.MapIndex(key), sep)))
+		}
+		sort.Strings(items)
+		return strings.Join(items, sep)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// columnWidths returns the natural width of each column.
+func columnWidths(cells [][]string, n int) []int {
+	widths := make([]int, n)
+	for _, line := range cells {
+		for i, cell := range line {
+			if w := utf8.RuneCountInString(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	return widths
+}
+
+// fitColumnWidths shrinks the widest columns until the table fits in maxWidth.
+// Columns never shrink below minColumnWidth. A maxWidth of 0 means no limit.
+func fitColumnWidths(widths []int, maxWidth int) {
+	if maxWidth <= 0 || len(widths) == 0 {
+		return
+	}
+
+	total := tableColumnGap * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// formatTableLine pads and truncates cells to the given widths.
+func formatTableLine(line []string, widths []int) string {
+	var sb strings.Builder
+	for i, cell := range line {
+		cell = truncate(cell, widths[i])
+		sb.WriteString(cell)
+		if i < len(line)-1 {
+			pad := widths[i] - utf8.RuneCountInString(cell) + tableColumnGap
+			sb.WriteString(strings.Repeat(" ", pad))
+		}
+	}
+	return strings.TrimRight(sb.String(), " ") + "\n"
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ellipsis
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type tableRepo struct {
+	Name      string
+	Status    string `table:"STATE"`
+	Stars     int
+	Secret    string `table:"-"`
+	UpdatedBy string
+}
+
+func TestTable_StructSlice(t *testing.T) {
+	data := []tableRepo{
+		{Name: "gzh-cli-core", Status: "clean", Stars: 10, Secret: "x"},
+		{Name: "gz-git", Status: "dirty", Stars: 3, UpdatedBy: "bot"},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), buf.String())
+	}
+	if lines[0] != "NAME           STATE   STARS   UPDATED BY" {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if lines[1] != "gzh-cli-core   clean   10" {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+	if strings.Contains(buf.String(), "SECRET") {
+		t.Errorf("expected table:\"-\" field to be skipped, got: %s", buf.String())
+	}
+}
+
+func TestTable_NoHeader(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table").SetNoHeader(true)
+	if err := out.Print([]tableRepo{{Name: "a", Status: "ok"}}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "NAME") {
+		t.Errorf("expected no header, got: %s", buf.String())
+	}
+	if !strings.HasPrefix(buf.String(), "a   ok") {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestTable_Maps(t *testing.T) {
+	data := []map[string]interface{}{
+		{"name": "a", "count": 1},
+		{"name": "b", "extra": true},
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table")
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	header := strings.SplitN(buf.String(), "\n", 2)[0]
+	if header != "COUNT   EXTRA   NAME" {
+		t.Errorf("expected sorted map columns, got: %q", header)
+	}
+}
+
+func TestTable_TruncatesToWidth(t *testing.T) {
+	data := []tableRepo{{Name: strings.Repeat("x", 40), Status: "ok"}}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table").SetMaxWidth(40)
+	if err := out.Print(data); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if n := len([]rune(line)); n > 40 {
+			t.Errorf("line exceeds max width (%d): %q", n, line)
+		}
+	}
+	if !strings.Contains(buf.String(), "…") {
+		t.Errorf("expected ellipsis in truncated output, got: %s", buf.String())
+	}
+}
+
+func TestTable_EmbeddedAndPrimitives(t *testing.T) {
+	type Base struct {
+		ID string
+	}
+	type Item struct {
+		Base
+		Tags []string
+	}
+
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat("table")
+	if err := out.Print([]*Item{{Base: Base{ID: "1"}, Tags: []string{"go", "cli"}}, nil}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "ID   TAGS\n1    go, cli\n") {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := out.Print([]string{"a", "b"}); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+	if buf.String() != "VALUE\na\nb\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helpers available to template output.
+var templateFuncs = template.FuncMap{
+	"join":  templateJoin,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"json":  templateJSON,
+	"default": func(def, v interface{}) interface{} {
+		if rv := reflect.ValueOf(v); !rv.IsValid() || rv.IsZero() {
+			return def
+		}
+		return v
+	},
+}
+
+// printTemplate renders data with a text/template, executed once on the
+// whole value as with kubectl's go-template output, so lists are walked
+// with range, e.g. "template={{range .}}{{.Name}} {{end}}". A trailing
+// newline is added if the output lacks one.
+func (o *Output) printTemplate(text string, data interface{}) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("template format requires a template, e.g. template={{.Name}}")
+	}
+
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	_, err = o.dataWriter().Write(buf.Bytes())
+	return err
+}
+
+// templateJoin joins the elements of any slice with sep.
+func templateJoin(sep string, v interface{}) string {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return ""
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return valueString(rv, sep)
+	}
+	items := make([]string, rv.Len())
+	for i := range items {
+		items[i] = valueString(rv.Index(i), sep)
+	}
+	return strings.Join(items, sep)
+}
+
+// templateJSON encodes v as compact JSON.
+func templateJSON(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type templateRepo struct {
+	Name   string   `json:"name"`
+	Topics []string `json:"topics"`
+	Stars  int      `json:"stars"`
+}
+
+func templateRepos() []templateRepo {
+	return []templateRepo{
+		{Name: "gz-git", Topics: []string{"git", "cli"}, Stars: 3},
+		{Name: "gzh-cli-core", Topics: []string{"lib"}, Stars: 10},
+	}
+}
+
+func TestTemplate_Range(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat(`template={{len .}} repos:{{range .}} {{.Name | upper}} ({{join "," .Topics}}){{end}}`)
+	if err := out.Print(templateRepos()); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	want := "2 repos: GZ-GIT (git,cli) GZH-CLI-CORE (lib)\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTemplate_JSONHelper(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOutput().SetWriter(&buf).SetFormat(`TEMPLATE={{json .Topics}}`)
+	if err := out.Print(templateRepos()[0]); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	if buf.String() != "[\"git\",\"cli\"]\n" {
+		t.Errorf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	out := NewOutput().SetWriter(&bytes.Buffer{})
+
+	if err := out.SetFormat("template=").Print(templateRepos()); err == nil {
+		t.Error("expected error for empty template")
+	}
+	if err := out.SetFormat("template={{.Name").Print(templateRepos()); err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]interface{}{"items": templateRepos()}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"{.items[*].name}", "gz-git gzh-cli-core\n"},
+		{"{.items[0].topics}", "[\"git\",\"cli\"]\n"},
+		{"{.items[-1].stars}", "10\n"},
+		{"{.items[0:1].name}", "gz-git\n"},
+		{"{..stars}", "3 10\n"},
+		{"{$.items[1]['name']}", "gzh-cli-core\n"},
+		{".items[1].name", "gzh-cli-core\n"},
+		{"first={.items[0].name} count={.items[0].stars}", "first=gz-git count=3\n"},
+		{"{.missing}", "\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		out := NewOutput().SetWriter(&buf).SetFormat("jsonpath=" + tt.expr)
+		if err := out.Print(data); err != nil {
+			t.Fatalf("Print(%q) failed: %v", tt.expr, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("jsonpath %q: got %q, want %q", tt.expr, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestJSONPath_Errors(t *testing.T) {
+	out := NewOutput().SetWriter(&bytes.Buffer{})
+
+	for _, expr := range []string{"", "{.items", "{.items[x]}", "{.items[0}"} {
+		if err := out.SetFormat("jsonpath=" + expr).Print(templateRepos()); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// defaultTerminalWidth is used when a terminal does not report its size.
+const defaultTerminalWidth = 80
+
+// isTerminal reports whether v, a reader or writer, is connected to a terminal.
+func isTerminal(v interface{}) bool {
+	f, ok := v.(*os.File)
+	return ok && f != nil && isTerminalFd(f)
+}
+
+// terminalWidth returns the column count of the terminal behind w.
+// Returns 0 when w is not a terminal, meaning output should not be truncated.
+func terminalWidth(w io.Writer) int {
+	if !isTerminal(w) {
+		return 0
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	if width := termWidth(w.(*os.File).Fd()); width > 0 {
+		return width
+	}
+	return defaultTerminalWidth
+}
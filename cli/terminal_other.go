@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cli
+
+import "os"
+
+// isTerminalFd reports whether f is a character device, the best guess
+// available on this platform.
+func isTerminalFd(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// termWidth is not supported on this platform.
+func termWidth(fd uintptr) int {
+	return 0
+}
+
+// disableEcho is not supported on this platform; input stays visible.
+func disableEcho(fd uintptr) (restore func(), err error) {
+	return func() {}, nil
+}
@@ -0,0 +1,51 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package cli
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminalFd reports whether f is a terminal, i.e. has terminal
+// attributes. Other character devices, such as /dev/null, do not.
+func isTerminalFd(f *os.File) bool {
+	var t syscall.Termios
+	return ioctlTermios(f.Fd(), ioctlReadTermios, &t) == nil
+}
+
+// termWidth queries the terminal size via TIOCGWINSZ.
+func termWidth(fd uintptr) int {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.Col)
+}
+
+// disableEcho turns off echoing of typed characters on the terminal fd, e.g.
+// while a password is entered, and returns a function restoring the state.
+func disableEcho(fd uintptr) (restore func(), err error) {
+	var old syscall.Termios
+	if err := ioctlTermios(fd, ioctlReadTermios, &old); err != nil {
+		return nil, err
+	}
+	noEcho := old
+	noEcho.Lflag &^= syscall.ECHO
+	if err := ioctlTermios(fd, ioctlWriteTermios, &noEcho); err != nil {
+		return nil, err
+	}
+	return func() { _ = ioctlTermios(fd, ioctlWriteTermios, &old) }, nil
+}
+
+func ioctlTermios(fd, request uintptr, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/version"
+)
+
+const (
+	// DefaultUpdateCheckInterval is how long an update check result is reused.
+	DefaultUpdateCheckInterval = 24 * time.Hour
+	// updateCheckTimeout bounds a single call to VersionOptions.LatestVersion.
+	updateCheckTimeout = 2 * time.Second
+)
+
+// VersionInfo holds extended version information.
+type VersionInfo = version.Info
+
+// VersionOptions configures AddVersionCmdWithOptions.
+type VersionOptions struct {
+	// LatestVersion returns the newest released version, e.g. from the
+	// GitHub releases API. Nil disables the update notice.
+	LatestVersion func(ctx context.Context) (string, error)
+
+	// CacheFile stores the result of the last check. Defaults to
+	// version-check.json in the root command's user cache directory.
+	CacheFile string
+
+	// CheckInterval is how long a cached result is used before checking
+	// again. Defaults to DefaultUpdateCheckInterval.
+	CheckInterval time.Duration
+}
+
+// AddVersionCmd adds a version subcommand with extended info.
+// See AddVersionCmdWithOptions.
+func AddVersionCmd(root *cobra.Command, info VersionInfo) *cobra.Command {
+	return AddVersionCmdWithOptions(root, info, VersionOptions{})
+}
+
+// AddVersionCmdWithOptions adds a version subcommand printing info
+// (typically version.Get()). It supports:
+//
+//   - --format json, yaml or llm for machine-readable output;
+//   - --short to print only the version number;
+//   - --deps to include the module dependencies from the build info.
+//
+// With opts.LatestVersion set, the command also reports when a newer
+// version is available: as a notice on stderr in text output, and as
+// latest_version otherwise. Results are cached for opts.CheckInterval, and
+// failed checks are silently ignored.
+func AddVersionCmdWithOptions(root *cobra.Command, info VersionInfo, opts VersionOptions) *cobra.Command {
+	var format string
+	var short, deps bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if short {
+				fmt.Fprintln(cmd.OutOrStdout(), info.Version)
+				return nil
+			}
+
+			info := info
+			if deps {
+				info.Dependencies = version.Dependencies()
+			}
+			if latest := latestVersion(cmd.Context(), root.Name(), opts); version.Compare(latest, info.Version) > 0 {
+				info.LatestVersion = latest
+			}
+
+			out := NewOutput().SetWriter(cmd.OutOrStdout()).SetErrorWriter(cmd.ErrOrStderr()).SetFormat(format)
+			if !out.isText() {
+				return out.Print(info)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), info.String())
+			if info.LatestVersion != "" {
+				notice := NewOutput().SetWriter(cmd.ErrOrStderr()).SetQuiet(quietFlag(cmd))
+				notice.Info("A newer version of %s is available: %s (current: %s)", root.Name(), info.LatestVersion, info.Version)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "", "Output format: text, json, yaml, llm")
+	cmd.Flags().BoolVar(&short, "short", false, "Print only the version number")
+	cmd.Flags().BoolVar(&deps, "deps", false, "Include module dependency versions")
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]cobra.Completion{"text", "json", "yaml", "llm"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.MarkFlagsMutuallyExclusive("short", "format")
+
+	root.AddCommand(cmd)
+	return cmd
+}
+
+// versionCheck is the cached result of an update check.
+type versionCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+// latestVersion returns the newest released version, from the cache while
+// it is fresh, or "" if unknown.
+func latestVersion(ctx context.Context, appName string, opts VersionOptions) string {
+	if opts.LatestVersion == nil {
+		return ""
+	}
+	path := opts.CacheFile
+	if path == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		path = filepath.Join(dir, appName, "version-check.json")
+	}
+	interval := opts.CheckInterval
+	if interval <= 0 {
+		interval = DefaultUpdateCheckInterval
+	}
+
+	var cached versionCheck
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cached)
+	}
+	if time.Since(cached.CheckedAt) < interval {
+		return cached.Latest
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	latest, err := opts.LatestVersion(ctx)
+	if err != nil {
+		return cached.Latest
+	}
+
+	data, _ := json.Marshal(versionCheck{CheckedAt: time.Now(), Latest: latest})
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+		_ = os.WriteFile(path, data, 0o644)
+	}
+	return latest
+}
+
+// quietFlag reports whether cmd was run with --quiet.
+func quietFlag(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup("quiet")
+	return (f != nil && f.Value.String() == "true") || defaultQuiet
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+func runVersionCmd(t *testing.T, opts VersionOptions, args ...string) (stdout, stderr string) {
+	t.Helper()
+	root := &cobra.Command{Use: "app"}
+	AddVersionCmdWithOptions(root, VersionInfo{Version: "1.2.0", GitCommit: "abc1234", Platform: "linux/amd64"}, opts)
+
+	var out, errOut bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs(append([]string{"version"}, args...))
+	if err := root.Execute(); err != nil {
+		t.Fatalf("version %v: %v", args, err)
+	}
+	return out.String(), errOut.String()
+}
+
+func TestVersionCmd_Formats(t *testing.T) {
+	stdout, _ := runVersionCmd(t, VersionOptions{})
+	if !strings.Contains(stdout, "Version:    1.2.0") || !strings.Contains(stdout, "Git Commit: abc1234") {
+		t.Errorf("unexpected text output:\n%s", stdout)
+	}
+
+	stdout, _ = runVersionCmd(t, VersionOptions{}, "--short")
+	if stdout != "1.2.0\n" {
+		t.Errorf("--short = %q", stdout)
+	}
+
+	stdout, _ = runVersionCmd(t, VersionOptions{}, "--format", "json")
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, stdout)
+	}
+	if got["version"] != "1.2.0" || got["git_commit"] != "abc1234" {
+		t.Errorf("unexpected json: %v", got)
+	}
+	if _, ok := got["dependencies"]; ok {
+		t.Error("dependencies should only be included with --deps")
+	}
+
+	stdout, _ = runVersionCmd(t, VersionOptions{}, "--format", "yaml")
+	if !strings.Contains(stdout, "version: 1.2.0") {
+		t.Errorf("unexpected yaml:\n%s", stdout)
+	}
+}
+
+func TestVersionCmd_UpdateNotice(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "check.json")
+	calls := 0
+	opts := VersionOptions{
+		CacheFile: cache,
+		LatestVersion: func(context.Context) (string, error) {
+			calls++
+			return "v1.3.0", nil
+		},
+	}
+
+	_, stderr := runVersionCmd(t, opts)
+	if !strings.Contains(stderr, "A newer version of app is available: v1.3.0 (current: 1.2.0)") {
+		t.Errorf("expected update notice, got %q", stderr)
+	}
+
+	stdout, _ := runVersionCmd(t, opts, "--format", "json")
+	if !strings.Contains(stdout, `"latest_version": "v1.3.0"`) {
+		t.Errorf("expected latest_version in json:\n%s", stdout)
+	}
+	if calls != 1 {
+		t.Errorf("expected cached result to be reused, got %d checks", calls)
+	}
+
+	_, stderr = runVersionCmd(t, opts, "--short")
+	if stderr != "" {
+		t.Errorf("--short should not show a notice, got %q", stderr)
+	}
+}
+
+func TestLatestVersion_CacheAndErrors(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), "nested", "check.json")
+	latest := "v1.0.0"
+	var fail bool
+	opts := VersionOptions{
+		CacheFile:     cache,
+		CheckInterval: time.Nanosecond,
+		LatestVersion: func(context.Context) (string, error) {
+			if fail {
+				return "", errors.New("offline")
+			}
+			return latest, nil
+		},
+	}
+
+	if got := latestVersion(context.Background(), "app", opts); got != "v1.0.0" {
+		t.Errorf("got %q", got)
+	}
+	latest = "v1.1.0"
+	if got := latestVersion(context.Background(), "app", opts); got != "v1.1.0" {
+		t.Errorf("expired cache should be refreshed, got %q", got)
+	}
+	fail = true
+	if got := latestVersion(context.Background(), "app", opts); got != "v1.1.0" {
+		t.Errorf("failed check should fall back to the cache, got %q", got)
+	}
+	if got := latestVersion(context.Background(), "app", VersionOptions{}); got != "" {
+		t.Errorf("no checker should mean no version, got %q", got)
+	}
+}
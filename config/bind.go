@@ -0,0 +1,369 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithEnv enables or disables the environment overrides applied with
+// WithLayers (enabled by default). See BindEnv.
+func (l *Loader) WithEnv(enabled bool) *Loader {
+	l.noEnv = !enabled
+	return l
+}
+
+// WithEnvPrefix sets the prefix of override variables, without the trailing
+// underscore. Defaults to GZH_<APP>, e.g. GZH_GZ_GIT for "gz-git".
+func (l *Loader) WithEnvPrefix(prefix string) *Loader {
+	l.envPrefix = prefix
+	return l
+}
+
+// EnvPrefix returns the prefix of override variables.
+func (l *Loader) EnvPrefix() string {
+	if l.envPrefix != "" {
+		return l.envPrefix
+	}
+	return DefaultEnvPrefix + "_" + envName(l.appName)
+}
+
+// ApplyEnv applies environment overrides to dst, unless disabled with
+// WithEnv(false) or dst is not a pointer to a struct. The Load methods call
+// it after reading the config files.
+func (l *Loader) ApplyEnv(dst interface{}) error {
+	if l.noEnv || !isStructPtr(dst) {
+		return nil
+	}
+	return bindStruct(reflect.ValueOf(dst).Elem(), l.EnvPrefix(), "", func(key, env string) {
+		l.setSource(key, Source{Kind: SourceEnv, Env: env})
+	})
+}
+
+// BindEnv overrides the fields of the struct dst points to from environment
+// variables named PREFIX_PATH, where PATH is the field's YAML key path in
+// upper case with "_" separators:
+//
+//	type Config struct {
+//		Port   int `yaml:"port"`              // GZH_MYAPP_PORT
+//		Server struct {
+//			Timeout time.Duration `yaml:"timeout"` // GZH_MYAPP_SERVER_TIMEOUT
+//		} `yaml:"server"`
+//		Labels map[string]string `yaml:"labels"`  // GZH_MYAPP_LABELS_<KEY>
+//		Token  string `yaml:"token" env:"GITHUB_TOKEN"`
+//	}
+//
+// An env tag names the variable explicitly and takes precedence. Empty
+// variables are ignored, as with GetEnv.
+//
+// Values are converted like the GetEnv helpers: booleans accept true, 1,
+// yes and on (and their opposites), durations use time.ParseDuration, and
+// slices are comma-separated lists. Types implementing
+// encoding.TextUnmarshaler parse themselves. Map entries of scalar type are
+// set from every variable starting with the map's prefix, the rest of the
+// name lower-cased giving the key, except the variables of sibling fields
+// sharing that prefix (GZH_MYAPP_LABELS_EXTRA_* for a LabelsExtra field);
+// map entries of struct type are only
+// overridden for keys that already exist.
+func BindEnv(dst interface{}, prefix string) error {
+	if !isStructPtr(dst) {
+		return fmt.Errorf("bind env: dst must be a pointer to a struct, got %T", dst)
+	}
+	return bindStruct(reflect.ValueOf(dst).Elem(), prefix, "", func(key, env string) {})
+}
+
+// bindStruct binds the fields of v to the variables below prefix, calling
+// record with the YAML path and variable name of each value set. key is the
+// YAML path of v.
+func bindStruct(v reflect.Value, prefix, key string, record func(key, env string)) error {
+	t := v.Type()
+	siblings := fieldEnvPaths(t, prefix)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline, skip := yamlKey(field)
+		if skip {
+			continue
+		}
+		path, fieldKey := prefix, key
+		if !inline {
+			path = joinEnv(prefix, envName(name))
+			fieldKey = joinPath(key, name)
+		}
+
+		if name := field.Tag.Get("env"); name != "" && name != "-" {
+			if value := os.Getenv(name); value != "" {
+				if err := setValue(v.Field(i), value); err != nil {
+					return fmt.Errorf("invalid %s: %w", name, err)
+				}
+				record(fieldKey, name)
+				continue
+			}
+		}
+		if err := bindValue(v.Field(i), path, fieldKey, siblings, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindValue applies the variable named path, or the variables below it for
+// structs and maps, to v. siblings are the paths of the fields next to v,
+// whose variables maps leave alone.
+func bindValue(v reflect.Value, path, key string, siblings []string, record func(key, env string)) error {
+	if value := os.Getenv(path); value != "" && isScalar(v.Type()) {
+		if err := setValue(v, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+		record(key, path)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return bindStruct(v, path, key, record)
+	case reflect.Ptr:
+		if v.Type().Elem().Kind() != reflect.Struct || !hasEnvPrefix(path+"_") {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return bindStruct(v.Elem(), path, key, record)
+	case reflect.Map:
+		return bindMap(v, path, key, siblings, record)
+	}
+	return nil
+}
+
+// bindMap sets map entries from variables below path, except those of the
+// sibling fields whose paths extend path, e.g. APP_LABELS_EXTRA for a map
+// at APP_LABELS.
+func bindMap(v reflect.Value, path, key string, siblings []string, record func(key, env string)) error {
+	t := v.Type()
+	if t.Key().Kind() != reflect.String {
+		return nil
+	}
+
+	// Entries of struct type: only existing keys can be told apart.
+	if !isScalar(t.Elem()) {
+		for _, k := range v.MapKeys() {
+			elem := reflect.New(t.Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+			if err := bindValue(elem, joinEnv(path, envName(k.String())), joinPath(key, k.String()), nil, record); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, elem)
+		}
+		return nil
+	}
+
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(name, path+"_")
+		if !ok || rest == "" || value == "" || isSiblingEnv(name, path, siblings) {
+			continue
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := setValue(elem, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+		v.SetMapIndex(reflect.ValueOf(strings.ToLower(rest)).Convert(t.Key()), elem)
+		record(joinPath(key, strings.ToLower(rest)), name)
+	}
+	return nil
+}
+
+// fieldEnvPaths returns the variable paths of the fields of t below prefix,
+// including those of inlined structs.
+func fieldEnvPaths(t reflect.Type, prefix string) []string {
+	var paths []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline, skip := yamlKey(field)
+		switch {
+		case skip:
+		case !inline:
+			paths = append(paths, joinEnv(prefix, envName(name)))
+		case field.Type.Kind() == reflect.Struct:
+			paths = append(paths, fieldEnvPaths(field.Type, prefix)...)
+		}
+	}
+	return paths
+}
+
+// isSiblingEnv reports whether the variable name belongs to a sibling of
+// the field at path whose path is longer.
+func isSiblingEnv(name, path string, siblings []string) bool {
+	for _, sibling := range siblings {
+		if strings.HasPrefix(sibling, path+"_") && (name == sibling || strings.HasPrefix(name, sibling+"_")) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isScalar reports whether values of t are set from a single variable.
+func isScalar(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return false
+	case reflect.Ptr:
+		return isScalar(t.Elem())
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Struct && t.Elem().Kind() != reflect.Map
+	}
+	return true
+}
+
+// setValue parses s into v, as for environment variables and default tags.
+func setValue(v reflect.Value, s string) error {
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Kind() == reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := parseEnvBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// parseEnvBool parses the values accepted by GetEnvBool and their opposites.
+func parseEnvBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "on":
+		return true, nil
+	case "false", "0", "no", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// yamlKey returns the YAML key of a struct field as yaml.v3 derives it.
+func yamlKey(field reflect.StructField) (key string, inline, skip bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "inline" {
+			return "", true, false
+		}
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, false, false
+}
+
+// envName converts a name to environment variable form, e.g. "gz-git" to "GZ_GIT".
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+func joinEnv(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// hasEnvPrefix reports whether any non-empty variable starts with prefix.
+func hasEnvPrefix(prefix string) bool {
+	for _, kv := range os.Environ() {
+		if name, value, _ := strings.Cut(kv, "="); strings.HasPrefix(name, prefix) && value != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type bindConfig struct {
+	Name     string        `yaml:"name"`
+	Port     int           `yaml:"port"`
+	Debug    bool          `yaml:"debug"`
+	Ratio    float64       `yaml:"ratio"`
+	Limit    uint16        `yaml:"limit"`
+	Timeout  time.Duration `yaml:"timeout"`
+	Tags     []string      `yaml:"tags"`
+	Ports    []int         `yaml:"ports"`
+	Token    string        `yaml:"token" env:"BIND_TEST_TOKEN"`
+	Skipped  string        `yaml:"-"`
+	Retries  *int          `yaml:"retries"`
+	IP       net.IP        `yaml:"ip"`
+	MaxConns int           `yaml:"max-conns"`
+	Server   struct {
+		Host string `yaml:"host"`
+	} `yaml:"server"`
+	Proxy  *struct{ URL string } `yaml:"proxy"`
+	Labels map[string]string     `yaml:"labels"`
+	Remote map[string]struct {
+		URL string `yaml:"url"`
+	} `yaml:"remotes"`
+	Common `yaml:",inline"`
+}
+
+type Common struct {
+	Region string `yaml:"region"`
+}
+
+func TestBindEnv(t *testing.T) {
+	env := map[string]string{
+		"APP_NAME":               "from-env",
+		"APP_PORT":               "9090",
+		"APP_DEBUG":              "yes",
+		"APP_RATIO":              "0.5",
+		"APP_LIMIT":              "100",
+		"APP_TIMEOUT":            "1m30s",
+		"APP_TAGS":               "a, b,,c",
+		"APP_PORTS":              "80,443",
+		"BIND_TEST_TOKEN":        "secret",
+		"APP_TOKEN":              "ignored",
+		"APP_SKIPPED":            "ignored",
+		"APP_RETRIES":            "3",
+		"APP_IP":                 "10.0.0.1",
+		"APP_MAX_CONNS":          "7",
+		"APP_SERVER_HOST":        "example.com",
+		"APP_PROXY_URL":          "http://proxy",
+		"APP_LABELS_TEAM":        "infra",
+		"APP_REMOTES_ORIGIN_URL": "git@example.com:x.git",
+		"APP_REGION":             "eu",
+		"APP_LABELS_EMPTY":       "",
+	}
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+
+	cfg := bindConfig{Skipped: "kept", Labels: map[string]string{"env": "prod"}}
+	cfg.Remote = map[string]struct {
+		URL string `yaml:"url"`
+	}{"origin": {URL: "old"}}
+	if err := BindEnv(&cfg, "APP"); err != nil {
+		t.Fatalf("BindEnv failed: %v", err)
+	}
+
+	if cfg.Name != "from-env" || cfg.Port != 9090 || !cfg.Debug || cfg.Ratio != 0.5 || cfg.Limit != 100 {
+		t.Errorf("scalars not bound: %+v", cfg)
+	}
+	if cfg.Timeout != 90*time.Second {
+		t.Errorf("timeout = %v", cfg.Timeout)
+	}
+	if !reflect.DeepEqual(cfg.Tags, []string{"a", "b", "c"}) || !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("lists not bound: %v %v", cfg.Tags, cfg.Ports)
+	}
+	if cfg.Token != "secret" {
+		t.Errorf("env tag should take precedence, got %q", cfg.Token)
+	}
+	if cfg.Skipped != "kept" {
+		t.Errorf("yaml:\"-\" fields should be skipped, got %q", cfg.Skipped)
+	}
+	if cfg.Retries == nil || *cfg.Retries != 3 {
+		t.Errorf("pointer not bound: %v", cfg.Retries)
+	}
+	if cfg.IP.String() != "10.0.0.1" {
+		t.Errorf("TextUnmarshaler not used: %v", cfg.IP)
+	}
+	if cfg.MaxConns != 7 {
+		t.Errorf("dashes in keys should map to underscores, got %d", cfg.MaxConns)
+	}
+	if cfg.Server.Host != "example.com" || cfg.Proxy == nil || cfg.Proxy.URL != "http://proxy" {
+		t.Errorf("nested structs not bound: %+v %+v", cfg.Server, cfg.Proxy)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"env": "prod", "team": "infra"}) {
+		t.Errorf("map not bound: %v", cfg.Labels)
+	}
+	if cfg.Remote["origin"].URL != "git@example.com:x.git" {
+		t.Errorf("struct map entry not bound: %v", cfg.Remote)
+	}
+	if cfg.Region != "eu" {
+		t.Errorf("inline struct not bound: %q", cfg.Region)
+	}
+}
+
+func TestBindEnv_MapAndSiblingPrefix(t *testing.T) {
+	t.Setenv("APP_LABELS_TEAM", "infra")
+	t.Setenv("APP_LABELS_EXTRA_OWNER", "me")
+	t.Setenv("APP_LABELS_EXTRA", "x")
+
+	var cfg struct {
+		Labels      map[string]string `yaml:"labels"`
+		LabelsExtra map[string]string `yaml:"labels_extra"`
+	}
+	if err := BindEnv(&cfg, "APP"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"team": "infra"}) {
+		t.Errorf("unexpected labels: %v", cfg.Labels)
+	}
+	if !reflect.DeepEqual(cfg.LabelsExtra, map[string]string{"owner": "me"}) {
+		t.Errorf("unexpected extra labels: %v", cfg.LabelsExtra)
+	}
+}
+
+func TestBindEnv_Errors(t *testing.T) {
+	var cfg bindConfig
+	if err := BindEnv(cfg, "APP"); err == nil {
+		t.Error("expected error for non-pointer")
+	}
+
+	t.Setenv("APP_PORT", "many")
+	if err := BindEnv(&cfg, "APP"); err == nil || err.Error() == "" {
+		t.Error("expected error for invalid int")
+	}
+
+	t.Setenv("APP_PORT", "")
+	t.Setenv("APP_DEBUG", "maybe")
+	if err := BindEnv(&cfg, "APP"); err == nil {
+		t.Error("expected error for invalid bool")
+	}
+}
+
+func TestLoader_EnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("name: from-file\nport: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GZH_MY_APP_PORT", "8080")
+
+	l := NewLoader("my-app").WithPaths(path).WithLayers(true)
+	if l.EnvPrefix() != "GZH_MY_APP" {
+		t.Errorf("EnvPrefix = %q", l.EnvPrefix())
+	}
+
+	var cfg testConfig
+	if err := l.Load(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "from-file" || cfg.Port != 8080 {
+		t.Errorf("env should override the file, got %+v", cfg)
+	}
+
+	cfg = testConfig{}
+	if err := NewLoader("my-app").WithPaths().WithLayers(true).LoadOrDefault(&cfg); err != nil || cfg.Port != 8080 {
+		t.Errorf("env should apply without a file, got %+v (%v)", cfg, err)
+	}
+
+	cfg = testConfig{}
+	if err := l.WithEnv(false).Load(&cfg); err != nil || cfg.Port != 80 {
+		t.Errorf("WithEnv(false) should disable overrides, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("CUSTOM_PORT", "9")
+	cfg = testConfig{}
+	if err := l.WithEnv(true).WithEnvPrefix("CUSTOM").Load(&cfg); err != nil || cfg.Port != 9 {
+		t.Errorf("custom prefix not used, got %+v (%v)", cfg, err)
+	}
+
+	m := map[string]interface{}{}
+	if err := l.LoadFrom(path, &m); err != nil || m["name"] != "from-file" {
+		t.Errorf("non-struct destinations should load without overrides, got %v (%v)", m, err)
+	}
+
+	cfg = testConfig{}
+	if err := NewLoader("my-app").LoadFrom(path, &cfg); err != nil || cfg.Port != 80 {
+		t.Errorf("without layers LoadFrom should only decode the file, got %+v (%v)", cfg, err)
+	}
+}
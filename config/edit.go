@@ -0,0 +1,395 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// BackupSuffix is appended to the name of the copy of a config file kept by
+// File.Save and Save before they replace it.
+const BackupSuffix = ".bak"
+
+// File is a YAML config file opened for editing. Set and Delete change
+// single keys of the parsed document, so comments, key order and the
+// formatting of untouched values survive the round trip, apart from the
+// indentation, which is normalized to that of the first indented line.
+type File struct {
+	path   string
+	doc    yaml.Node
+	indent int
+}
+
+// EditFile opens the config file at path for editing. A missing file is
+// treated as empty and created by Save.
+//
+//	f, err := config.EditFile("config.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	if err := f.Set("server.port", 9090); err != nil {
+//		return err
+//	}
+//	return f.Save()
+func EditFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	f := &File{path: path, indent: detectIndent(data)}
+	if err := yaml.Unmarshal(data, &f.doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Path returns the path of the file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Set sets the value at key, creating missing mappings on the way. Keys are
+// dotted paths with optional list indexes, e.g. "server.port" or
+// "remotes[0].url"; an index one past the end of a list appends to it.
+// value is encoded as by yaml.Marshal. Replacing a mapping or list keeps
+// the comments of the keys and items that remain.
+func (f *File) Set(key string, value interface{}) error {
+	path, err := parseKeyPath(key)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	parent := f.root()
+	for i, elem := range path {
+		child, err := childNode(parent, elem, key, true)
+		if err != nil {
+			return err
+		}
+		if i == len(path)-1 {
+			syncNodes(child, &node)
+			return nil
+		}
+		parent = child
+	}
+	return nil
+}
+
+// Delete removes key, a path as for Set, and reports whether it existed.
+func (f *File) Delete(key string) (bool, error) {
+	path, err := parseKeyPath(key)
+	if err != nil {
+		return false, err
+	}
+	parent := f.root()
+	for _, elem := range path[:len(path)-1] {
+		child, err := childNode(parent, elem, key, false)
+		if err != nil || child == nil {
+			return false, err
+		}
+		parent = child
+	}
+
+	last := path[len(path)-1]
+	switch {
+	case last.index >= 0 && parent.Kind == yaml.SequenceNode:
+		if last.index >= len(parent.Content) {
+			return false, nil
+		}
+		parent.Content = append(parent.Content[:last.index], parent.Content[last.index+1:]...)
+		return true, nil
+	case last.index < 0 && parent.Kind == yaml.MappingNode:
+		i := mappingIndex(parent, last.key)
+		if i < 0 {
+			return false, nil
+		}
+		parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+		return true, nil
+	}
+	return false, nil
+}
+
+// Bytes returns the edited document.
+func (f *File) Bytes() ([]byte, error) {
+	if f.doc.Kind == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(f.indent)
+	if err := enc.Encode(&f.doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes the edited document back to the file. See WriteFile.
+func (f *File) Save() error {
+	data, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	return WriteFile(f.path, data)
+}
+
+// SetKey sets key in the config file at path, keeping its comments and
+// layout. See File.Set.
+func SetKey(path, key string, value interface{}) error {
+	f, err := EditFile(path)
+	if err != nil {
+		return err
+	}
+	if err := f.Set(key, value); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+// DeleteKey removes key from the config file at path, keeping its comments
+// and layout, and reports whether it existed. The file is only written if
+// it did. See File.Delete.
+func DeleteKey(path, key string) (bool, error) {
+	f, err := EditFile(path)
+	if err != nil {
+		return false, err
+	}
+	found, err := f.Delete(key)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, f.Save()
+}
+
+// WriteFile replaces the file at path with data atomically: data is written
+// to a temporary file in the same directory, which is then renamed over
+// path, so readers see either the old or the new content. An existing file
+// keeps its permissions and is first copied to path+BackupSuffix, in the
+// same way, so that read-only files and backups can be replaced too; new
+// files are created 0644 along with their directory. Symbolic links are
+// followed.
+func WriteFile(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+		old, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+		if err := writeAtomic(path+BackupSuffix, old, mode); err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := writeAtomic(path, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// writeAtomic writes data to a temporary file next to path, sets its mode
+// and renames it over path. Renaming does not need write permission on an
+// existing file, only on its directory.
+func writeAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// root returns the top-level node of the document, creating an empty
+// mapping for an empty document.
+func (f *File) root() *yaml.Node {
+	if f.doc.Kind == 0 {
+		f.doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(f.doc.Content) == 0 {
+		f.doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	return f.doc.Content[0]
+}
+
+// detectIndent returns the indentation of the first indented line of a
+// YAML document, or 2.
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 {
+			if n < 2 || n > 9 {
+				break
+			}
+			return n
+		}
+	}
+	return 2
+}
+
+// pathElem is a segment of a key path: a mapping key, or a list index when
+// index is not negative.
+type pathElem struct {
+	key   string
+	index int
+}
+
+// parseKeyPath splits a key such as "remotes[0].url".
+func parseKeyPath(key string) ([]pathElem, error) {
+	invalid := func() error {
+		return errors.Wrap(fmt.Errorf("key %q", key), errors.ErrInvalidInput)
+	}
+	var path []pathElem
+	for _, part := range strings.Split(key, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" && (len(path) == 0 || rest == "") {
+			return nil, invalid()
+		}
+		if name != "" {
+			path = append(path, pathElem{key: name, index: -1})
+		}
+		for rest != "" {
+			digits, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(digits)
+			if !ok || err != nil || n < 0 {
+				return nil, invalid()
+			}
+			path = append(path, pathElem{index: n})
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, invalid()
+			}
+			rest = after[1:]
+		}
+	}
+	return path, nil
+}
+
+// childNode returns the node at elem below parent. With create, missing
+// keys are added and empty values turned into mappings or lists as needed;
+// otherwise a missing node is nil.
+func childNode(parent *yaml.Node, elem pathElem, key string, create bool) (*yaml.Node, error) {
+	want, kind := yaml.MappingNode, "mapping"
+	if elem.index >= 0 {
+		want, kind = yaml.SequenceNode, "list"
+	}
+	if parent.Kind != want {
+		switch {
+		case !create:
+			return nil, nil
+		case !isNull(parent):
+			return nil, errors.Wrap(fmt.Errorf("key %q: line %d is not a %s", key, parent.Line, kind), errors.ErrInvalidInput)
+		}
+		*parent = yaml.Node{Kind: want, Tag: "!!map", HeadComment: parent.HeadComment, LineComment: parent.LineComment, FootComment: parent.FootComment}
+		if want == yaml.SequenceNode {
+			parent.Tag = "!!seq"
+		}
+	}
+
+	if elem.index >= 0 {
+		switch {
+		case elem.index < len(parent.Content):
+			return parent.Content[elem.index], nil
+		case create && elem.index == len(parent.Content):
+			child := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+			parent.Content = append(parent.Content, child)
+			return child, nil
+		case create:
+			return nil, errors.Wrap(fmt.Errorf("key %q: index %d out of range", key, elem.index), errors.ErrInvalidInput)
+		}
+		return nil, nil
+	}
+
+	if i := mappingIndex(parent, elem.key); i >= 0 {
+		return parent.Content[i+1], nil
+	}
+	if !create {
+		return nil, nil
+	}
+	child := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: elem.key}, child)
+	return child, nil
+}
+
+// syncNodes updates dst in place to hold the value of src, keeping the
+// comments and style of the parts of dst that remain: mapping keys present
+// in both, list items by position, and unchanged scalars.
+func syncNodes(dst, src *yaml.Node) {
+	if src.Kind == yaml.DocumentNode && len(src.Content) > 0 {
+		src = src.Content[0]
+	}
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		// Keys are kept in the order of dst; new keys go at the end.
+		content := make([]*yaml.Node, 0, len(src.Content))
+		for i := 0; i+1 < len(dst.Content); i += 2 {
+			key, value := dst.Content[i], dst.Content[i+1]
+			if j := mappingIndex(src, key.Value); j >= 0 {
+				syncNodes(value, src.Content[j+1])
+				content = append(content, key, value)
+			}
+		}
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			if mappingIndex(dst, src.Content[i].Value) < 0 {
+				content = append(content, src.Content[i], src.Content[i+1])
+			}
+		}
+		dst.Content = content
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				syncNodes(dst.Content[i], item)
+			} else {
+				dst.Content = append(dst.Content, item)
+			}
+		}
+		dst.Content = dst.Content[:len(src.Content)]
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		// Keep the quoting of strings that stay strings.
+		if dst.ShortTag() != src.ShortTag() {
+			dst.Tag, dst.Style = src.Tag, src.Style
+		}
+		dst.Value = src.Value
+	default:
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *src
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+	}
+}
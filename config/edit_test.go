@@ -0,0 +1,196 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+const editOriginal = `# App settings
+name: "app" # display name
+
+server:
+    # Listen port
+    port: 8080
+    host: localhost
+
+remotes:
+    - url: a
+    - url: b
+legacy: true
+`
+
+func TestFile_SetAndDelete(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yaml", editOriginal)
+
+	f, err := EditFile(path)
+	if err != nil {
+		t.Fatalf("EditFile failed: %v", err)
+	}
+	steps := []error{
+		f.Set("server.port", 9090),
+		f.Set("name", "renamed"),
+		f.Set("remotes[1].url", "c"),
+		f.Set("remotes[2]", map[string]string{"url": "d"}),
+		f.Set("labels.team", "core"),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("Set %d failed: %v", i, err)
+		}
+	}
+	if found, err := f.Delete("legacy"); err != nil || !found {
+		t.Fatalf("Delete(legacy) = %v, %v", found, err)
+	}
+	if found, err := f.Delete("missing.key"); err != nil || found {
+		t.Errorf("Delete(missing.key) = %v, %v", found, err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"# App settings\n",
+		`name: "renamed" # display name`,
+		"    # Listen port\n    port: 9090\n",
+		"    - url: c\n    - url: d\n",
+		"labels:\n    team: core\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "legacy") {
+		t.Errorf("legacy should be deleted:\n%s", got)
+	}
+	if strings.Index(got, "server:") > strings.Index(got, "remotes:") {
+		t.Errorf("key order not kept:\n%s", got)
+	}
+
+	backup, err := os.ReadFile(path + BackupSuffix)
+	if err != nil || string(backup) != editOriginal {
+		t.Errorf("expected backup of the original, got %q, %v", backup, err)
+	}
+}
+
+func TestFile_SetErrors(t *testing.T) {
+	f, err := EditFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("EditFile on a missing file failed: %v", err)
+	}
+	if err := f.Set("name", "app"); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "a..b", "name.first", "list[x]", "tags[3]"} {
+		if err := f.Set(key, 1); !errors.Is(err, errors.ErrInvalidInput) {
+			t.Errorf("Set(%q) = %v, want ErrInvalidInput", key, err)
+		}
+	}
+}
+
+func TestSetKey_KeepsPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not preserved on Windows")
+	}
+	path := writeFile(t, t.TempDir(), "config.yaml", "port: 1 # port\n")
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetKey(path, "port", 2); err != nil {
+		t.Fatalf("SetKey failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "port: 2 # port\n" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 2 {
+		t.Errorf("expected the file and its backup only, got %d entries", len(entries))
+	}
+}
+
+func TestSetKey_ReadOnlyFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not preserved on Windows")
+	}
+	path := writeFile(t, t.TempDir(), "config.yaml", "port: 1\n")
+	if err := os.Chmod(path, 0o400); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second save replaces the read-only backup left by the first.
+	for port := 2; port <= 3; port++ {
+		if err := SetKey(path, "port", port); err != nil {
+			t.Fatalf("SetKey(%d) failed: %v", port, err)
+		}
+	}
+	info, err := os.Stat(path + BackupSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o400 {
+		t.Errorf("backup mode = %v, want 0400", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path + BackupSuffix); string(data) != "port: 2\n" {
+		t.Errorf("unexpected backup %q", data)
+	}
+}
+
+func TestSave_KeepsComments(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yaml", `# My app
+name: old # the name
+port: 8080
+unknown: x
+`)
+	cfg := testConfig{Name: "new", Port: 9000}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	got := string(data)
+	for _, want := range []string{"# My app\n", "name: new # the name\n", "port: 9000\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "unknown") {
+		t.Errorf("keys not in cfg should be removed:\n%s", got)
+	}
+}
+
+func TestSave_KeepsKeyOrder(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "config.yaml", "# top\nport: 80 # the port\nname: app\n")
+	cfg := struct {
+		Name  string `yaml:"name"`
+		Port  int    `yaml:"port"`
+		Debug bool   `yaml:"debug"`
+	}{Name: "app", Port: 81}
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "# top\nport: 81 # the port\nname: app\ndebug: false\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
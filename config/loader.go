@@ -5,14 +5,23 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 
+	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 )
 
 // Loader provides configuration file loading utilities.
 type Loader struct {
-	appName string
-	paths   []string
+	appName   string
+	paths     []string
+	merge     bool
+	layers    bool
+	noEnv     bool
+	envPrefix string
+	flags     *pflag.FlagSet
+	sources   map[string]Source
 }
 
 // NewLoader creates a new configuration loader with the given app name.
@@ -41,6 +50,30 @@ func (l *Loader) PrependPath(path string) *Loader {
 	return l
 }
 
+// WithMerge enables merge mode: Load and LoadOrDefault deep-merge every
+// existing file in the search paths instead of loading only the first one.
+// See MergeFiles for the merge rules.
+func (l *Loader) WithMerge(merge bool) *Loader {
+	l.merge = merge
+	return l
+}
+
+// WithLayers makes the Load methods, LoadFrom and MergeFiles treat struct
+// destinations as layered configuration: default tags are applied before
+// the files, and environment overrides, flags and validate tags after them
+// (see ApplyDefaults, ApplyEnv, ApplyFlags and Validate). It is off by
+// default, so that they only decode the files; InstallBootstrap in package
+// cli turns it on.
+func (l *Loader) WithLayers(enabled bool) *Loader {
+	l.layers = enabled
+	return l
+}
+
+// Merging reports whether merge mode is enabled.
+func (l *Loader) Merging() bool {
+	return l.merge
+}
+
 // Paths returns the current search paths.
 func (l *Loader) Paths() []string {
 	return l.paths
@@ -48,7 +81,17 @@ func (l *Loader) Paths() []string {
 
 // Load loads configuration from the first existing file in the search paths.
 // The dst must be a pointer to a struct.
+//
+// In merge mode, all existing files are merged instead, the first search
+// path taking precedence.
 func (l *Loader) Load(dst interface{}) error {
+	if l.merge {
+		files := l.MergeOrder()
+		if len(files) == 0 {
+			return fmt.Errorf("no config file found in paths: %v", l.paths)
+		}
+		return l.MergeFiles(dst, files...)
+	}
 	for _, path := range l.paths {
 		if _, err := os.Stat(path); err == nil {
 			return l.LoadFrom(path, dst)
@@ -57,30 +100,96 @@ func (l *Loader) Load(dst interface{}) error {
 	return fmt.Errorf("no config file found in paths: %v", l.paths)
 }
 
-// LoadFrom loads configuration from a specific file path.
+// LoadFrom loads configuration from a specific file path. With WithLayers,
+// struct destinations also get defaults, environment overrides, flags and
+// validation. The source of each value is recorded for Explain.
 func (l *Loader) LoadFrom(path string, dst interface{}) error {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("failed to read config file %s: %w", path, err)
-	}
-
-	if err := yaml.Unmarshal(data, dst); err != nil {
-		return fmt.Errorf("failed to parse config file %s: %w", path, err)
-	}
-
-	return nil
+	return l.decode(dst, []string{path}, false)
 }
 
 // LoadOrDefault loads configuration, returning nil error if no file found.
-// Caller should initialize dst with default values before calling.
+// Defaults are the values already in dst and, with WithLayers, default
+// tags.
 func (l *Loader) LoadOrDefault(dst interface{}) error {
+	if l.merge {
+		return l.MergeFiles(dst, l.MergeOrder()...)
+	}
 	for _, path := range l.paths {
 		if _, err := os.Stat(path); err == nil {
 			return l.LoadFrom(path, dst)
 		}
 	}
 	// No config file found, dst retains its default values
-	return nil
+	return l.decode(dst, nil, false)
+}
+
+// decode loads the files into dst, later files taking precedence, merged
+// with the MergeFiles rules when merge is set. With layers, struct
+// destinations get defaults, environment overrides, flags and validation.
+func (l *Loader) decode(dst interface{}, paths []string, merge bool) error {
+	l.sources = map[string]Source{}
+	isStruct := isStructPtr(dst)
+	if isStruct && l.layers {
+		if err := ApplyDefaults(dst); err != nil {
+			return err
+		}
+	}
+
+	var root *yaml.Node
+	files := map[*yaml.Node]string{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		recordFile(files, doc.Content[0], path)
+		if merge {
+			root = mergeNodes(root, doc.Content[0])
+		} else {
+			root = doc.Content[0]
+		}
+	}
+
+	if root != nil {
+		if merge {
+			unsetNulls(root)
+		}
+		clearMergeTags(root)
+		if err := root.Decode(dst); err != nil {
+			if len(paths) == 1 {
+				return fmt.Errorf("failed to parse config file %s: %w", paths[0], err)
+			}
+			return fmt.Errorf("failed to decode merged config: %w", err)
+		}
+	}
+
+	if !isStruct {
+		return nil
+	}
+	l.sources = fileSources(root, files)
+	if !l.layers {
+		return nil
+	}
+	if err := l.ApplyEnv(dst); err != nil {
+		return err
+	}
+	if err := l.ApplyFlags(dst, l.flags); err != nil {
+		return err
+	}
+	return validate(dst, l.sources)
+}
+
+// isStructPtr reports whether v is a non-nil pointer to a struct.
+func isStructPtr(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct
 }
 
 // FindConfigFile returns the first existing config file path.
@@ -93,7 +202,26 @@ func (l *Loader) FindConfigFile() (string, bool) {
 	return "", false
 }
 
-// DefaultPaths returns default configuration search paths for the given app.
+// FindConfigFiles returns every existing config file in the search paths,
+// in search order. Paths naming the same file are listed once.
+func (l *Loader) FindConfigFiles() []string {
+	seen := map[string]bool{}
+	var files []string
+	for _, path := range l.paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if key := fileKey(path); !seen[key] {
+			seen[key] = true
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// DefaultPaths returns default configuration search paths for the given app,
+// from highest to lowest precedence: project files in the working
+// directory, then user files, then system files.
 func DefaultPaths(appName string) []string {
 	paths := []string{
 		appName + ".yaml",
@@ -120,25 +248,77 @@ func DefaultPaths(appName string) []string {
 		)
 	}
 
+	// Add system-wide paths
+	if dir := systemConfigDir(); dir != "" {
+		paths = append(paths,
+			filepath.Join(dir, appName, "config.yaml"),
+			filepath.Join(dir, appName, "config.yml"),
+		)
+	}
+
 	return paths
 }
 
-// Save saves configuration to the given path.
-func Save(path string, cfg interface{}) error {
-	data, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+// systemConfigDir returns the directory for system-wide configuration:
+// /etc, or %ProgramData% on Windows.
+func systemConfigDir() string {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("ProgramData")
+	}
+	return "/etc"
+}
+
+// MergeOrder returns the files merged in merge mode, from lowest to highest
+// precedence: the files found in the search paths, in reverse search order,
+// then extra, e.g. the file given with --config. A file named both in the
+// search paths and in extra is merged once, at its position in extra.
+func (l *Loader) MergeOrder(extra ...string) []string {
+	override := map[string]bool{}
+	for _, path := range extra {
+		override[fileKey(path)] = true
+	}
+	var files []string
+	for _, path := range reversed(l.FindConfigFiles()) {
+		if !override[fileKey(path)] {
+			files = append(files, path)
+		}
 	}
+	return append(files, extra...)
+}
 
-	// Ensure parent directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+// fileKey identifies the file at path, for comparing paths.
+func fileKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
 	}
+	return path
+}
 
-	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+// reversed returns a reversed copy of s.
+func reversed(s []string) []string {
+	r := make([]string, len(s))
+	for i, v := range s {
+		r[len(s)-1-i] = v
 	}
+	return r
+}
 
-	return nil
+// Save saves configuration to the given path.
+//
+// If the file exists, it is updated in place: keys kept from the old file
+// retain their comments, order and quoting, keys no longer set are removed
+// and new ones appended. The file is replaced atomically, keeping its
+// permissions and a backup copy (see WriteFile).
+func Save(path string, cfg interface{}) error {
+	f, err := EditFile(path)
+	if err != nil {
+		// Replace an unreadable file; WriteFile keeps a backup.
+		f = &File{path: path, indent: 2}
+	}
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	syncNodes(f.root(), &node)
+	return f.Save()
 }
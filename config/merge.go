@@ -0,0 +1,98 @@
+package config
+
+import "gopkg.in/yaml.v3"
+
+// Tags controlling how a value is merged over the same key of a
+// lower-precedence file.
+const (
+	// TagAppend appends a list to the inherited list instead of replacing it:
+	//
+	//	plugins: !append [extra]
+	TagAppend = "!append"
+	// TagReplace replaces an inherited map instead of merging into it.
+	TagReplace = "!replace"
+)
+
+// MergeFiles deep-merges the YAML files into dst, later files taking
+// precedence over earlier ones, e.g. system, user, project, then the file
+// given with --config:
+//
+//   - maps are merged key by key, recursively, unless tagged !replace;
+//   - lists replace the inherited list, unless tagged !append;
+//   - an explicit null (key: ~ or key: null) unsets the inherited value, so
+//     the default in dst is kept;
+//   - any other value replaces the inherited one.
+//
+// Files that are empty are skipped. As with LoadFrom, struct destinations
+// get defaults, environment overrides, flags and validation with
+// WithLayers.
+func (l *Loader) MergeFiles(dst interface{}, paths ...string) error {
+	return l.decode(dst, paths, true)
+}
+
+// mergeNodes merges src over dst and returns the result, reusing dst.
+func mergeNodes(dst, src *yaml.Node) *yaml.Node {
+	if dst == nil {
+		return src
+	}
+	switch {
+	case src.Kind == yaml.MappingNode && dst.Kind == yaml.MappingNode && src.Tag != TagReplace:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			if j := mappingIndex(dst, key.Value); j >= 0 {
+				// The key takes the position of the overriding file.
+				dst.Content[j] = key
+				dst.Content[j+1] = mergeNodes(dst.Content[j+1], value)
+			} else {
+				dst.Content = append(dst.Content, key, value)
+			}
+		}
+		return dst
+	case src.Kind == yaml.SequenceNode && dst.Kind == yaml.SequenceNode && src.Tag == TagAppend:
+		dst.Content = append(dst.Content, src.Content...)
+		return dst
+	default:
+		return src
+	}
+}
+
+// mappingIndex returns the index of key in a mapping node, or -1.
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// unsetNulls removes mapping entries whose value is an explicit null.
+func unsetNulls(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		content := node.Content[:0]
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if isNull(node.Content[i+1]) {
+				continue
+			}
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+		node.Content = content
+	}
+	for _, child := range node.Content {
+		unsetNulls(child)
+	}
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null"
+}
+
+// clearMergeTags removes the merge tags so the node decodes normally.
+func clearMergeTags(node *yaml.Node) {
+	if node.Tag == TagAppend || node.Tag == TagReplace {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		clearMergeTags(child)
+	}
+}
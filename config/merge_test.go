@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type mergeConfig struct {
+	Name    string            `yaml:"name"`
+	Port    int               `yaml:"port"`
+	Tags    []string          `yaml:"tags"`
+	Plugins []string          `yaml:"plugins"`
+	Labels  map[string]string `yaml:"labels"`
+	Server  struct {
+		Host string `yaml:"host"`
+		TLS  bool   `yaml:"tls"`
+	} `yaml:"server"`
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoader_MergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	system := writeFile(t, dir, "system.yaml", `
+name: system
+port: 80
+tags: [a, b]
+plugins: [core]
+labels: {team: infra, env: prod}
+server: {host: example.com, tls: true}
+`)
+	user := writeFile(t, dir, "user.yaml", `
+port: 8080
+tags: [c]
+plugins: !append [extra]
+labels: !replace {owner: me}
+server:
+  tls: false
+`)
+	project := writeFile(t, dir, "project.yaml", `
+name: ~
+server:
+  host: localhost
+`)
+	empty := writeFile(t, dir, "empty.yaml", "")
+
+	cfg := mergeConfig{Name: "default"}
+	if err := NewLoader("app").MergeFiles(&cfg, system, user, empty, project); err != nil {
+		t.Fatalf("MergeFiles failed: %v", err)
+	}
+
+	if cfg.Name != "default" {
+		t.Errorf("null should unset the inherited name, got %q", cfg.Name)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("port = %d, want 8080", cfg.Port)
+	}
+	if !reflect.DeepEqual(cfg.Tags, []string{"c"}) {
+		t.Errorf("lists should be replaced, got %v", cfg.Tags)
+	}
+	if !reflect.DeepEqual(cfg.Plugins, []string{"core", "extra"}) {
+		t.Errorf("!append should append, got %v", cfg.Plugins)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"owner": "me"}) {
+		t.Errorf("!replace should replace the map, got %v", cfg.Labels)
+	}
+	if cfg.Server.Host != "localhost" || cfg.Server.TLS {
+		t.Errorf("maps should merge recursively, got %+v", cfg.Server)
+	}
+}
+
+func TestLoader_MergeFiles_Errors(t *testing.T) {
+	dir := t.TempDir()
+	bad := writeFile(t, dir, "bad.yaml", "name: [unclosed\n")
+
+	var cfg mergeConfig
+	if err := NewLoader("app").MergeFiles(&cfg, bad); err == nil {
+		t.Error("expected parse error")
+	}
+	if err := NewLoader("app").MergeFiles(&cfg, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected read error")
+	}
+}
+
+func TestLoader_WithMerge(t *testing.T) {
+	dir := t.TempDir()
+	project := writeFile(t, dir, "app.yaml", "port: 9000\n")
+	user := writeFile(t, dir, "home/config.yaml", "name: user\nport: 8000\n")
+
+	l := NewLoader("app").WithPaths(project, filepath.Join(dir, "missing.yaml"), user, user).WithMerge(true)
+	if files := l.FindConfigFiles(); !reflect.DeepEqual(files, []string{project, user}) {
+		t.Errorf("FindConfigFiles = %v", files)
+	}
+
+	var cfg mergeConfig
+	if err := l.Load(&cfg); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Name != "user" || cfg.Port != 9000 {
+		t.Errorf("project should override user config, got %+v", cfg)
+	}
+
+	empty := NewLoader("app").WithPaths(filepath.Join(dir, "none.yaml")).WithMerge(true)
+	if err := empty.Load(&cfg); err == nil {
+		t.Error("expected error when no file exists")
+	}
+	if err := empty.LoadOrDefault(&cfg); err != nil {
+		t.Errorf("LoadOrDefault should ignore missing files: %v", err)
+	}
+}
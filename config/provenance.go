@@ -0,0 +1,238 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// SourceKind identifies the kind of source that set a configuration value.
+type SourceKind string
+
+// Source kinds, from lowest to highest precedence.
+const (
+	SourceDefault SourceKind = "default"
+	SourceFile    SourceKind = "file"
+	SourceEnv     SourceKind = "env"
+	SourceFlag    SourceKind = "flag"
+)
+
+// Source describes where a configuration value came from.
+type Source struct {
+	Kind SourceKind `json:"kind" yaml:"kind"`
+	// File and Line locate values set by a config file.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line int    `json:"line,omitempty" yaml:"line,omitempty"`
+	// Env names the variable of values set by the environment.
+	Env string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Flag names the flag of values set on the command line.
+	Flag string `json:"flag,omitempty" yaml:"flag,omitempty"`
+}
+
+// String returns e.g. "config.yaml:12", "env GZH_APP_PORT", "flag --port"
+// or "default".
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceFile:
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	case SourceEnv:
+		return "env " + s.Env
+	case SourceFlag:
+		return "flag --" + s.Flag
+	}
+	return string(SourceDefault)
+}
+
+// Setting is an effective configuration value and its source.
+type Setting struct {
+	Key    string      `json:"key" yaml:"key"`
+	Value  interface{} `json:"value" yaml:"value"`
+	Source Source      `json:"source" yaml:"source"`
+}
+
+// WithFlags sets the command-line flags applied by the Load methods after
+// environment overrides. See ApplyFlags.
+func (l *Loader) WithFlags(fs *pflag.FlagSet) *Loader {
+	l.flags = fs
+	return l
+}
+
+// ApplyFlags sets the fields of the struct dst points to that have a flag
+// tag from the flags of fs given on the command line:
+//
+//	Timeout time.Duration `yaml:"timeout" flag:"timeout"`
+//
+// Values are converted as for BindEnv; slice flags set slices item by item.
+// Flags not given keep the value from the files, environment or defaults.
+func (l *Loader) ApplyFlags(dst interface{}, fs *pflag.FlagSet) error {
+	if fs == nil || !isStructPtr(dst) {
+		return nil
+	}
+	var errs []error
+	walkFields(reflect.ValueOf(dst).Elem(), "", func(path string, field reflect.StructField, v reflect.Value) {
+		name := field.Tag.Get("flag")
+		if name == "" || name == "-" {
+			return
+		}
+		f := fs.Lookup(name)
+		if f == nil || !f.Changed {
+			return
+		}
+		if err := setFlagValue(v, f); err != nil {
+			errs = append(errs, fmt.Errorf("invalid --%s: %w", name, err))
+			return
+		}
+		l.setSource(path, Source{Kind: SourceFlag, Flag: name})
+	})
+	return errors.Join(errs...)
+}
+
+// setFlagValue sets v from the value of f.
+func setFlagValue(v reflect.Value, f *pflag.Flag) error {
+	sv, ok := f.Value.(pflag.SliceValue)
+	if !ok || v.Kind() != reflect.Slice || reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return setValue(v, f.Value.String())
+	}
+	items := sv.GetSlice()
+	slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setValue(slice.Index(i), item); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+// Sources returns the source of every value set by the last Load call that
+// did not come from a default, keyed by YAML path as in Explain. Values of
+// lists and maps have entries both for the whole value and for each item,
+// e.g. "remotes" and "remotes[0].url".
+func (l *Loader) Sources() map[string]Source {
+	sources := make(map[string]Source, len(l.sources))
+	for key, src := range l.sources {
+		sources[key] = src
+	}
+	return sources
+}
+
+// Source returns the source of the value at key, e.g. "server.port", as
+// set by the last Load call. Values set by no file, variable or flag are
+// defaults.
+func (l *Loader) Source(key string) Source {
+	if src, ok := l.sources[key]; ok {
+		return src
+	}
+	return Source{Kind: SourceDefault}
+}
+
+// Explain returns the effective value and source of every setting in the
+// struct dst points to, as loaded by the last Load call, in field order.
+// Nested structs are expanded into their fields, and maps of scalars into
+// their entries; lists of scalars are single settings.
+func (l *Loader) Explain(dst interface{}) []Setting {
+	if !isStructPtr(dst) {
+		return nil
+	}
+	var settings []Setting
+	add := func(key string, v reflect.Value) {
+		settings = append(settings, Setting{Key: key, Value: settingValue(v), Source: l.Source(key)})
+	}
+	walkFields(reflect.ValueOf(dst).Elem(), "", func(path string, field reflect.StructField, v reflect.Value) {
+		t := v.Type()
+		switch {
+		case isScalar(t):
+			add(path, v)
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isScalar(t.Elem()):
+			for _, key := range sortedKeys(v) {
+				add(joinPath(path, key.String()), v.MapIndex(key))
+			}
+		}
+	})
+	return settings
+}
+
+// settingValue returns v in a form that prints well in every output format.
+func settingValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return v.Interface()
+}
+
+// setSource records src for key, replacing the sources of the values
+// below it.
+func (l *Loader) setSource(key string, src Source) {
+	if l.sources == nil {
+		l.sources = map[string]Source{}
+	}
+	for k := range l.sources {
+		if strings.HasPrefix(k, key+".") || strings.HasPrefix(k, key+"[") {
+			delete(l.sources, k)
+		}
+	}
+	l.sources[key] = src
+}
+
+// sortedKeys returns the keys of the map v in order.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+// recordFile maps node and all nodes below it to file.
+func recordFile(files map[*yaml.Node]string, node *yaml.Node, file string) {
+	files[node] = file
+	for _, child := range node.Content {
+		recordFile(files, child, file)
+	}
+}
+
+// fileSources returns the file and line of every value below root, keyed
+// by YAML path as produced by walkFields.
+func fileSources(root *yaml.Node, files map[*yaml.Node]string) map[string]Source {
+	sources := map[string]Source{}
+	// Mapping values are located by their key, which records the file
+	// that set them last.
+	var visit func(node *yaml.Node, path string, at *yaml.Node)
+	visit = func(node *yaml.Node, path string, at *yaml.Node) {
+		if path != "" {
+			sources[path] = Source{Kind: SourceFile, File: files[at], Line: at.Line}
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				visit(node.Content[i+1], joinPath(path, key.Value), key)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				visit(item, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+	}
+	if root != nil {
+		visit(root, "", root)
+	}
+	return sources
+}
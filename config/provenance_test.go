@@ -0,0 +1,108 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+type explainConfig struct {
+	Name    string            `yaml:"name"`
+	Port    int               `yaml:"port" default:"8080" flag:"port"`
+	Timeout time.Duration     `yaml:"timeout" default:"30s"`
+	Tags    []string          `yaml:"tags" flag:"tag"`
+	Labels  map[string]string `yaml:"labels"`
+	Server  struct {
+		Host string `yaml:"host"`
+	} `yaml:"server"`
+}
+
+func TestLoader_Explain(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "base.yaml", `name: base
+server:
+  host: example.com
+`)
+	user := writeFile(t, dir, "user.yaml", `# user settings
+name: user
+labels:
+  team: core
+`)
+	t.Setenv("GZH_EXPLAIN_TIMEOUT", "5s")
+	t.Setenv("GZH_EXPLAIN_LABELS_ENV", "dev")
+
+	fs := pflag.NewFlagSet("explain", pflag.ContinueOnError)
+	fs.Int("port", 0, "")
+	fs.StringSlice("tag", nil, "")
+	if err := fs.Parse([]string{"--tag", "a,b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg explainConfig
+	l := NewLoader("explain").WithLayers(true).WithFlags(fs)
+	if err := l.MergeFiles(&cfg, base, user); err != nil {
+		t.Fatalf("MergeFiles failed: %v", err)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[1] != "b" {
+		t.Errorf("flag not applied: %v", cfg.Tags)
+	}
+
+	got := map[string]string{}
+	for _, s := range l.Explain(&cfg) {
+		got[s.Key] = s.Source.String()
+	}
+	want := map[string]string{
+		"name":        filepath.Join(dir, "user.yaml") + ":2",
+		"port":        "default",
+		"timeout":     "env GZH_EXPLAIN_TIMEOUT",
+		"tags":        "flag --tag",
+		"labels.team": filepath.Join(dir, "user.yaml") + ":4",
+		"labels.env":  "env GZH_EXPLAIN_LABELS_ENV",
+		"server.host": filepath.Join(dir, "base.yaml") + ":3",
+	}
+	for key, src := range want {
+		if got[key] != src {
+			t.Errorf("source of %s = %q, want %q", key, got[key], src)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("settings = %v", got)
+	}
+
+	settings := l.Explain(&cfg)
+	if settings[0].Key != "name" || settings[2].Key != "timeout" || settings[2].Value != "5s" {
+		t.Errorf("settings out of order or unformatted: %+v", settings[:3])
+	}
+	if src := l.Source("name"); src.Kind != SourceFile || src.Line != 2 {
+		t.Errorf("Source(name) = %+v", src)
+	}
+}
+
+func TestLoader_ApplyFlags(t *testing.T) {
+	fs := pflag.NewFlagSet("flags", pflag.ContinueOnError)
+	fs.String("port", "", "")
+	if err := fs.Parse([]string{"--port", "http"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg explainConfig
+	err := NewLoader("flags").WithLayers(true).WithEnv(false).WithFlags(fs).LoadOrDefault(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "--port") {
+		t.Errorf("expected error naming the flag, got %v", err)
+	}
+}
+
+func TestLoader_ValidateEnvSource(t *testing.T) {
+	t.Setenv("GZH_VALIDATE_PORT", "70000")
+
+	var cfg validateConfig
+	cfg.Name = "app"
+	cfg.Retries = 1
+	err := NewLoader("validate").WithPaths().WithLayers(true).LoadOrDefault(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "env GZH_VALIDATE_PORT: ") {
+		t.Errorf("expected violation prefixed with the variable, got %v", err)
+	}
+}
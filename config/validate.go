@@ -0,0 +1,277 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+// ApplyDefaults sets each zero-valued field of the struct dst points to
+// from its default tag, converting the value as BindEnv does:
+//
+//	type Config struct {
+//		Timeout time.Duration `yaml:"timeout" default:"30s"`
+//		Tags    []string      `yaml:"tags" default:"a,b"`
+//	}
+//
+// Nested structs are handled recursively; nil pointers to structs are left
+// alone. The Load methods call it before reading the config files, so values
+// in the files take precedence.
+func ApplyDefaults(dst interface{}) error {
+	if !isStructPtr(dst) {
+		return fmt.Errorf("apply defaults: dst must be a pointer to a struct, got %T", dst)
+	}
+	var errs []error
+	walkFields(reflect.ValueOf(dst).Elem(), "", func(path string, field reflect.StructField, v reflect.Value) {
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !v.IsZero() {
+			return
+		}
+		if err := setValue(v, def); err != nil {
+			errs = append(errs, fmt.Errorf("invalid default for %s: %w", path, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Validate checks the fields of the struct dst points to against their
+// validate tags and returns every violation, joined in an error matching
+// errors.ErrInvalidConfig. Rules are separated by commas:
+//
+//	required     the value must not be zero
+//	omitempty    skip the other rules when the value is zero
+//	min=N        minimum number, duration, or length of a string, slice or map
+//	max=N        maximum, as for min
+//	oneof=a|b|c  the value must be one of the alternatives
+//
+// For example:
+//
+//	Port int    `yaml:"port" validate:"required,min=1,max=65535"`
+//	Mode string `yaml:"mode" validate:"oneof=fast|safe"`
+//
+// The Load methods call it after loading, and prefix each violation with
+// the source that set the value, e.g. "config.yaml:3: port must be between
+// 1 and 65535" or "env GZH_APP_PORT: ...".
+func Validate(dst interface{}) error {
+	if !isStructPtr(dst) {
+		return fmt.Errorf("validate: dst must be a pointer to a struct, got %T", dst)
+	}
+	return validate(dst, nil)
+}
+
+// validate validates dst, prefixing violations with their source in sources.
+func validate(dst interface{}, sources map[string]Source) error {
+	var errs []error
+	walkFields(reflect.ValueOf(dst).Elem(), "", func(path string, field reflect.StructField, v reflect.Value) {
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			return
+		}
+		for _, err := range checkRules(path, rules, v) {
+			if src, ok := sources[path]; ok {
+				err = fmt.Errorf("%s: %w", src, err)
+			}
+			errs = append(errs, err)
+		}
+	})
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Wrap(errors.Join(errs...), errors.ErrInvalidConfig)
+}
+
+// checkRules returns the violations of a validate tag by v.
+func checkRules(name, rules string, v reflect.Value) []error {
+	var min, max string
+	var errs []error
+	for _, rule := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch rule {
+		case "required":
+			if v.IsZero() {
+				return []error{errors.EmptyValue(name)}
+			}
+		case "omitempty":
+			if v.IsZero() {
+				return nil
+			}
+		case "min":
+			min = arg
+		case "max":
+			max = arg
+		case "oneof":
+			options := strings.Split(arg, "|")
+			value := fmt.Sprint(v.Interface())
+			if !containsString(options, value) {
+				errs = append(errs, errors.InvalidValue(name, value, "must be one of "+strings.Join(options, ", ")))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown validation rule %q", name, rule))
+		}
+	}
+	if min != "" || max != "" {
+		if err := checkBounds(name, v, min, max); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// checkBounds checks v against min and max, either of which may be empty.
+func checkBounds(name string, v reflect.Value, min, max string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return checkDuration(name, time.Duration(v.Int()), min, max)
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		return checkNumber(name, float64(v.Int()), min, max)
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		return checkNumber(name, float64(v.Uint()), min, max)
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return checkNumber(name, v.Float(), min, max)
+	case v.Kind() == reflect.String || v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		return checkNumber(name+" length", float64(v.Len()), min, max)
+	}
+	return fmt.Errorf("%s: min and max are not supported for %s", name, v.Type())
+}
+
+func checkNumber(name string, value float64, min, max string) error {
+	lo, hi, err := parseBounds(min, max, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if (min == "" || value >= lo) && (max == "" || value <= hi) {
+		return nil
+	}
+
+	loInt, hiInt := int(lo), int(hi)
+	if float64(loInt) != lo || float64(hiInt) != hi {
+		return errors.InvalidValue(name, strconv.FormatFloat(value, 'g', -1, 64), boundsReason(min, max))
+	}
+	switch {
+	case min != "" && max != "":
+		return errors.Range(name, loInt, hiInt)
+	case min != "":
+		return errors.MinValue(name, loInt)
+	default:
+		return errors.MaxValue(name, hiInt)
+	}
+}
+
+func checkDuration(name string, value time.Duration, min, max string) error {
+	lo, hi, err := parseBounds(min, max, time.ParseDuration)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if (min == "" || value >= lo) && (max == "" || value <= hi) {
+		return nil
+	}
+	return errors.InvalidValue(name, value.String(), boundsReason(min, max))
+}
+
+// parseBounds parses the non-empty bounds with parse.
+func parseBounds[T any](min, max string, parse func(string) (T, error)) (lo, hi T, err error) {
+	if min != "" {
+		if lo, err = parse(min); err != nil {
+			return lo, hi, fmt.Errorf("invalid min %q", min)
+		}
+	}
+	if max != "" {
+		if hi, err = parse(max); err != nil {
+			return lo, hi, fmt.Errorf("invalid max %q", max)
+		}
+	}
+	return lo, hi, nil
+}
+
+func boundsReason(min, max string) string {
+	switch {
+	case min != "" && max != "":
+		return fmt.Sprintf("must be between %s and %s", min, max)
+	case min != "":
+		return "must be at least " + min
+	default:
+		return "must be at most " + max
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// walkFields calls fn for every exported field of the struct v with its
+// YAML path, e.g. "server.port", then descends into nested structs,
+// non-nil pointers to structs, and slices and maps of structs, whose
+// elements get paths like "remotes[0].url" and "profiles.work.token".
+func walkFields(v reflect.Value, prefix string, fn func(path string, field reflect.StructField, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, inline, skip := yamlKey(field)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if inline {
+			walkElem(fv, prefix, fn)
+			continue
+		}
+		path := joinPath(prefix, key)
+		fn(path, field, fv)
+		walkElem(fv, path, fn)
+	}
+}
+
+// walkElem descends into the structs contained in v.
+func walkElem(v reflect.Value, path string, fn func(path string, field reflect.StructField, v reflect.Value)) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if !isScalar(v.Type()) {
+			walkFields(v, path, fn)
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			walkElem(v.Elem(), path, fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkElem(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		for _, key := range sortedKeys(v) {
+			// Map values are not addressable; walk a copy and store it back.
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			walkElem(elem, joinPath(path, key.String()), fn)
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-core/errors"
+)
+
+type validateConfig struct {
+	Name    string        `yaml:"name" validate:"required"`
+	Port    int           `yaml:"port" default:"8080" validate:"min=1,max=65535"`
+	Debug   bool          `yaml:"debug" default:"true"`
+	Timeout time.Duration `yaml:"timeout" default:"30s" validate:"min=1s,max=5m"`
+	Mode    string        `yaml:"mode" default:"fast" validate:"oneof=fast|safe"`
+	Tags    []string      `yaml:"tags" default:"a,b" validate:"max=3"`
+	Ratio   float64       `yaml:"ratio" validate:"omitempty,min=0.1"`
+	Retries int           `yaml:"retries" validate:"min=1"`
+	Server  struct {
+		Host string `yaml:"host" default:"localhost" validate:"required"`
+	} `yaml:"server"`
+	Remotes []struct {
+		URL string `yaml:"url" validate:"required"`
+	} `yaml:"remotes"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	cfg := validateConfig{Port: 9000}
+	if err := ApplyDefaults(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 9000 {
+		t.Errorf("existing values should be kept, got %d", cfg.Port)
+	}
+	if !cfg.Debug || cfg.Timeout != 30*time.Second || cfg.Mode != "fast" || len(cfg.Tags) != 2 || cfg.Server.Host != "localhost" {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+
+	var bad struct {
+		Port int `default:"many"`
+	}
+	if err := ApplyDefaults(&bad); err == nil {
+		t.Error("expected error for invalid default")
+	}
+}
+
+func TestLoader_DefaultsAndValidation(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", `name: app
+debug: false
+timeout: 10s
+retries: 2
+`)
+
+	var cfg validateConfig
+	if err := NewLoader("app").WithLayers(true).WithEnv(false).LoadFrom(path, &cfg); err != nil {
+		t.Fatalf("LoadFrom failed: %v", err)
+	}
+	if cfg.Debug {
+		t.Error("explicit false in the file should override a true default")
+	}
+	if cfg.Port != 8080 || cfg.Timeout != 10*time.Second {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoader_ValidationErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "config.yaml", `port: 70000
+timeout: 500ms
+mode: turbo
+tags: [a, b, c, d]
+ratio: 0.01
+server:
+  host: ""
+remotes:
+  - url: git@example.com:a.git
+  - url: ""
+`)
+
+	var cfg validateConfig
+	err := NewLoader("app").WithLayers(true).WithEnv(false).LoadFrom(path, &cfg)
+	if !errors.Is(err, errors.ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	want := []string{
+		"name cannot be empty",
+		path + ":1: port must be between 1 and 65535",
+		path + `:2: invalid timeout "500ms": must be between 1s and 5m`,
+		path + `:3: invalid mode "turbo": must be one of fast, safe`,
+		path + ":4: tags length must be at most 3",
+		path + `:5: invalid ratio "0.01": must be at least 0.1`,
+		"retries must be at least 1",
+		path + ":7: server.host cannot be empty",
+		path + ":10: remotes[1].url cannot be empty",
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("error missing %q:\n%v", w, err)
+		}
+	}
+	if strings.Contains(err.Error(), "remotes[0]") {
+		t.Errorf("valid entries should not be reported:\n%v", err)
+	}
+}
+
+func TestLoader_ValidationMerged(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "base.yaml", "name: app\nretries: 1\nport: 80\n")
+	override := writeFile(t, dir, "override.yaml", "\nport: 0\n")
+
+	var cfg validateConfig
+	err := NewLoader("app").WithLayers(true).WithEnv(false).MergeFiles(&cfg, base, override)
+	if err == nil || !strings.Contains(err.Error(), filepath.Base(override)+":2: port must be between 1 and 65535") {
+		t.Errorf("expected error located in the overriding file, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := validateConfig{Name: "x", Port: 1, Timeout: time.Second, Mode: "safe", Retries: 1}
+	cfg.Server.Host = "h"
+	if err := Validate(&cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var bad struct {
+		Name string `validate:"bogus"`
+	}
+	if err := Validate(&bad); err == nil || !strings.Contains(err.Error(), `unknown validation rule "bogus"`) {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+	if err := Validate(cfg); err == nil {
+		t.Error("expected error for non-pointer")
+	}
+}
@@ -0,0 +1,141 @@
+package errors
+
+import "errors"
+
+// Coder is implemented by errors that carry their own machine-readable code.
+type Coder interface {
+	Code() string
+}
+
+// codes maps sentinel errors to the codes reported by Code.
+var codes = []struct {
+	target error
+	code   string
+}{
+	{ErrUsage, "USAGE"},
+	{ErrConfigNotFound, "CONFIG_NOT_FOUND"},
+	{ErrInvalidConfig, "INVALID_CONFIG"},
+	{ErrNotFound, "NOT_FOUND"},
+	{ErrInvalidInput, "INVALID_INPUT"},
+	{ErrUnauthorized, "UNAUTHORIZED"},
+	{ErrTimeout, "TIMEOUT"},
+	{ErrPermission, "PERMISSION_DENIED"},
+	{ErrAlreadyExists, "ALREADY_EXISTS"},
+	{ErrNotSupported, "NOT_SUPPORTED"},
+}
+
+// Code returns a machine-readable code for err, e.g. "NOT_FOUND".
+// An error implementing Coder anywhere in the chain takes precedence over
+// the sentinel errors; errors matching neither return "ERROR".
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		if code := coder.Code(); code != "" {
+			return code
+		}
+	}
+	for _, c := range codes {
+		if errors.Is(err, c.target) {
+			return c.code
+		}
+	}
+	return "ERROR"
+}
+
+// usageError marks an error as caused by invalid command-line usage.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string        { return e.err.Error() }
+func (e *usageError) Unwrap() error        { return e.err }
+func (e *usageError) Is(target error) bool { return target == ErrUsage }
+
+// Usage marks err as a usage error (a bad flag, argument or combination of
+// them), so that errors.Is(err, ErrUsage) reports true. The message is
+// unchanged. If err is nil, returns nil.
+func Usage(err error) error {
+	if err == nil || errors.Is(err, ErrUsage) {
+		return err
+	}
+	return &usageError{err: err}
+}
+
+// hintError attaches hints to an error.
+type hintError struct {
+	err   error
+	hints []string
+}
+
+func (e *hintError) Error() string { return e.err.Error() }
+func (e *hintError) Unwrap() error { return e.err }
+
+// WithHint attaches hints telling the user how to resolve err, e.g.
+// "run 'gz-git auth login' first". The error message is unchanged.
+// If err is nil, returns nil.
+func WithHint(err error, hints ...string) error {
+	if err == nil {
+		return nil
+	}
+	return &hintError{err: err, hints: hints}
+}
+
+// Hints returns every hint attached to err or the errors it wraps,
+// outermost first.
+func Hints(err error) []string {
+	var hints []string
+	walk(err, func(e error) {
+		if h, ok := e.(*hintError); ok {
+			hints = append(hints, h.hints...)
+		}
+	})
+	return hints
+}
+
+// Causes returns the messages of the errors wrapped by err, outermost first.
+// Wrappers that do not change the message, such as WithHint, are skipped.
+func Causes(err error) []string {
+	var causes []string
+	last := ""
+	if err != nil {
+		last = err.Error()
+	}
+	// Start below err rather than comparing errors, which may not be
+	// comparable.
+	for _, child := range unwrapAll(err) {
+		walk(child, func(e error) {
+			if msg := e.Error(); msg != last {
+				causes = append(causes, msg)
+				last = msg
+			}
+		})
+	}
+	return causes
+}
+
+// walk calls fn for err and every error in its tree, depth first.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	for _, child := range unwrapAll(err) {
+		walk(child, fn)
+	}
+}
+
+// unwrapAll returns the errors err wraps directly.
+func unwrapAll(err error) []error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if child := e.Unwrap(); child != nil {
+			return []error{child}
+		}
+	case interface{ Unwrap() []error }:
+		return e.Unwrap()
+	}
+	return nil
+}
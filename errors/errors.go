@@ -17,6 +17,7 @@ var (
 	ErrPermission     = errors.New("permission denied")
 	ErrAlreadyExists  = errors.New("already exists")
 	ErrNotSupported   = errors.New("not supported")
+	ErrUsage          = errors.New("invalid usage")
 )
 
 // Wrap combines two errors, preserving the chain for errors.Is/As.
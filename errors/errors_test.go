@@ -152,6 +152,7 @@ func TestSentinelErrors(t *testing.T) {
 		ErrPermission,
 		ErrAlreadyExists,
 		ErrNotSupported,
+		ErrUsage,
 	}
 
 	for _, err := range sentinels {
@@ -276,3 +277,105 @@ func TestInvalidValue(t *testing.T) {
 		t.Error("expected field name in error without reason")
 	}
 }
+
+type codedError struct{}
+
+func (codedError) Error() string { return "rate limited" }
+func (codedError) Code() string  { return "RATE_LIMITED" }
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{New("boom"), "ERROR"},
+		{ErrNotFound, "NOT_FOUND"},
+		{WrapWithMessage(ErrConfigNotFound, "load"), "CONFIG_NOT_FOUND"},
+		{WrapOp("fetch", fmt.Errorf("%w", ErrTimeout)), "TIMEOUT"},
+		{Wrap(codedError{}, ErrUnauthorized), "RATE_LIMITED"},
+	}
+
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWithHint(t *testing.T) {
+	if WithHint(nil, "hint") != nil {
+		t.Error("WithHint(nil) should return nil")
+	}
+
+	err := WithHint(ErrUnauthorized, "run 'app login' first")
+	err = WrapOp("fetch repos", WithHint(err, "check GITHUB_TOKEN"))
+
+	if err.Error() != "fetch repos failed: unauthorized" {
+		t.Errorf("hints should not change the message, got %q", err.Error())
+	}
+	if !Is(err, ErrUnauthorized) {
+		t.Error("hinted error should match the wrapped sentinel")
+	}
+
+	hints := Hints(err)
+	if len(hints) != 2 || hints[0] != "check GITHUB_TOKEN" || hints[1] != "run 'app login' first" {
+		t.Errorf("unexpected hints: %v", hints)
+	}
+}
+
+func TestCauses(t *testing.T) {
+	err := WrapOp("sync", WithHint(WrapWithMessage(ErrNotFound, "repo foo"), "check the name"))
+
+	causes := Causes(err)
+	want := []string{"repo foo: not found", "not found"}
+	if len(causes) != len(want) {
+		t.Fatalf("Causes() = %v, want %v", causes, want)
+	}
+	for i := range want {
+		if causes[i] != want[i] {
+			t.Errorf("Causes()[%d] = %q, want %q", i, causes[i], want[i])
+		}
+	}
+
+	if causes := Causes(Join(New("a"), New("b"))); len(causes) != 2 {
+		t.Errorf("expected both joined errors as causes, got %v", causes)
+	}
+}
+
+// multiErr is a multi-error whose values cannot be compared with ==.
+type multiErr []error
+
+func (m multiErr) Error() string   { return "multiple errors" }
+func (m multiErr) Unwrap() []error { return m }
+
+func TestCauses_NonComparable(t *testing.T) {
+	err := multiErr{New("a"), New("b")}
+	if causes := Causes(err); len(causes) != 2 || causes[0] != "a" || causes[1] != "b" {
+		t.Errorf("Causes() = %v, want [a b]", causes)
+	}
+	if causes := Causes(WrapOp("sync", err)); len(causes) != 3 || causes[0] != "multiple errors" {
+		t.Errorf("Causes() = %v", causes)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if Usage(nil) != nil {
+		t.Error("Usage(nil) should return nil")
+	}
+
+	err := Usage(fmt.Errorf("bad value: %w", ErrInvalidInput))
+	if !Is(err, ErrUsage) || !Is(err, ErrInvalidInput) {
+		t.Error("usage error should match ErrUsage and the wrapped error")
+	}
+	if err.Error() != "bad value: invalid input" {
+		t.Errorf("Usage should not change the message, got %q", err.Error())
+	}
+	if Code(err) != "USAGE" {
+		t.Errorf("Code() = %q, want USAGE", Code(err))
+	}
+
+	if !Is(RequiredFlag("output"), ErrUsage) || !Is(MutuallyExclusive("a", "b"), ErrUsage) {
+		t.Error("flag validation errors should match ErrUsage")
+	}
+}
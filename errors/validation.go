@@ -31,6 +31,7 @@ func ValidationErrorf(format string, args ...interface{}) error {
 }
 
 // RequiredFlag returns a standardized required flag error with optional examples.
+// The error matches ErrUsage.
 func RequiredFlag(flagName string, examples ...string) error {
 	msg := fmt.Sprintf("--%s flag is required", flagName)
 	if len(examples) > 0 {
@@ -39,12 +40,13 @@ func RequiredFlag(flagName string, examples ...string) error {
 			msg += "\n  " + ex
 		}
 	}
-	return fmt.Errorf("%s", msg)
+	return Usage(fmt.Errorf("%s", msg))
 }
 
 // MutuallyExclusive returns an error for mutually exclusive flags.
+// The error matches ErrUsage.
 func MutuallyExclusive(flag1, flag2 string) error {
-	return fmt.Errorf("--%s and --%s cannot be used together", flag1, flag2)
+	return Usage(fmt.Errorf("--%s and --%s cannot be used together", flag1, flag2))
 }
 
 // MinValue returns an error for values below minimum.
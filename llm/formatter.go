@@ -0,0 +1,806 @@
+// Package llm renders Go values in a compact, token-efficient text format
+// intended for LLM consumption.
+//
+// Structs become UPPER_CASE labeled lines, slices of structs become numbered
+// blocks and zero values are omitted:
+//
+//	NAME: api
+//	PORTS: 80 | 443
+//	OWNER:
+//	  EMAIL: ops@example.com
+package llm
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxDepth is the nesting depth rendered when Options.MaxDepth is zero.
+	DefaultMaxDepth = 5
+	// DefaultIndentSize is the number of spaces per nesting level when
+	// Options.IndentSize is zero.
+	DefaultIndentSize = 2
+
+	// CharsPerToken approximates token counts for budgeted output.
+	CharsPerToken = 4
+
+	ellipsis = "…"
+)
+
+// Marshaler is implemented by types that control their own rendering in
+// the llm output format. Multi-line results are indented to the nesting level.
+type Marshaler interface {
+	MarshalLLM() string
+}
+
+// Options configures a Formatter. The zero value renders up to
+// DefaultMaxDepth levels with DefaultIndentSize spaces and no token limit.
+type Options struct {
+	// MaxDepth limits nesting; deeper values render as "...".
+	// Zero means DefaultMaxDepth; a negative value renders only the top level.
+	MaxDepth int
+
+	// IndentSize is the number of spaces per nesting level.
+	// Zero means DefaultIndentSize.
+	IndentSize int
+
+	// MaxTokens limits output to roughly MaxTokens tokens (CharsPerToken
+	// characters each). Zero means no limit.
+	MaxTokens int
+}
+
+// Formatter renders values in the llm format. It holds no per-call state and
+// is safe for concurrent use.
+type Formatter struct {
+	opts Options
+}
+
+// NewFormatter creates a Formatter with the given options.
+func NewFormatter(opts Options) *Formatter {
+	return &Formatter{opts: opts}
+}
+
+// Format renders data with default options.
+func Format(data interface{}) string {
+	return NewFormatter(Options{}).Format(data)
+}
+
+// Format renders data, staying within the configured token budget.
+//
+// Limits are tightened deterministically: first long slices and strings are
+// shortened, then nesting depth is reduced so top-level fields are kept, and
+// finally trailing lines are cut. An ELIDED line reports what was left out.
+func (f *Formatter) Format(data interface{}) string {
+	return f.FormatAt(data, 0)
+}
+
+// FormatAt renders data as if nested depth levels deep, e.g. depth 1 for an
+// item of an enclosing list. Depth counts against MaxDepth.
+func (f *Formatter) FormatAt(data interface{}, depth int) string {
+	if f.opts.MaxTokens <= 0 {
+		return f.newRenderer(0, 0, f.maxDepth()).format(data, depth)
+	}
+	maxChars := f.opts.MaxTokens * CharsPerToken
+
+	var output string
+	var elided elisions
+	try := func(r *renderer) bool {
+		output = r.format(data, depth)
+		elided = r.elided
+		return len(output)+len(elisionLine(elided)) <= maxChars
+	}
+
+	for _, step := range budgetSteps {
+		if try(f.newRenderer(step.items, step.stringLen, f.maxDepth())) {
+			return output + elisionLine(elided)
+		}
+	}
+
+	tightest := budgetSteps[len(budgetSteps)-1]
+	for maxDepth := f.maxDepth() - 1; maxDepth >= 0; maxDepth-- {
+		if try(f.newRenderer(tightest.items, tightest.stringLen, maxDepth)) {
+			return output + elisionLine(elided)
+		}
+	}
+
+	// Still too long: keep leading lines, reserving room for the summary
+	lines := strings.SplitAfter(strings.TrimSuffix(output, "\n"), "\n")
+	var sb strings.Builder
+	for i, line := range lines {
+		remaining := elided
+		remaining.lines += len(lines) - i
+		if sb.Len()+len(line)+len(elisionLine(remaining)) > maxChars {
+			elided = remaining
+			break
+		}
+		sb.WriteString(line)
+	}
+	if s := sb.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		sb.WriteString("\n")
+	}
+	return sb.String() + elisionLine(elided)
+}
+
+// Indent returns the indentation for the given nesting depth.
+func (f *Formatter) Indent(depth int) string {
+	size := f.opts.IndentSize
+	if size <= 0 {
+		size = DefaultIndentSize
+	}
+	return strings.Repeat(" ", depth*size)
+}
+
+// maxDepth resolves the configured depth limit.
+func (f *Formatter) maxDepth() int {
+	switch {
+	case f.opts.MaxDepth < 0:
+		return 0
+	case f.opts.MaxDepth == 0:
+		return DefaultMaxDepth
+	default:
+		return f.opts.MaxDepth
+	}
+}
+
+func (f *Formatter) newRenderer(maxItems, maxStringLen, maxDepth int) *renderer {
+	return &renderer{
+		formatter:    f,
+		maxDepth:     maxDepth,
+		maxItems:     maxItems,
+		maxStringLen: maxStringLen,
+		active:       make(map[visit]string),
+	}
+}
+
+// Label converts a CamelCase field name to its UPPER_CASE label.
+func Label(name string) string {
+	var result strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			// Check if we should insert underscore
+			prev := rune(name[i-1])
+			if unicode.IsLower(prev) {
+				result.WriteRune('_')
+			} else if i+1 < len(name) {
+				next := rune(name[i+1])
+				if unicode.IsLower(next) {
+					result.WriteRune('_')
+				}
+			}
+		}
+		result.WriteRune(unicode.ToUpper(r))
+	}
+	return result.String()
+}
+
+// FieldLabel returns the label a struct field is rendered with: the label
+// from its llm tag, or Label of its name.
+func FieldLabel(field reflect.StructField) string {
+	if label := parseTag(field).label; label != "" {
+		return label
+	}
+	return Label(field.Name)
+}
+
+// fieldOptions holds options parsed from an `llm:"label,keepzero,omit,inline"` tag.
+type fieldOptions struct {
+	label    string
+	keepZero bool
+	omit     bool
+	inline   bool
+}
+
+// parseTag parses the llm struct tag of a field. A label of "-" omits the field.
+func parseTag(field reflect.StructField) fieldOptions {
+	tag, ok := field.Tag.Lookup("llm")
+	if !ok {
+		return fieldOptions{}
+	}
+
+	parts := strings.Split(tag, ",")
+	opts := fieldOptions{label: parts[0]}
+	if opts.label == "-" {
+		opts.label = ""
+		opts.omit = true
+	}
+	for _, opt := range parts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "keepzero":
+			opts.keepZero = true
+		case "omit":
+			opts.omit = true
+		case "inline":
+			opts.inline = true
+		}
+	}
+	return opts
+}
+
+// elided counts what a renderer left out.
+type elisions struct {
+	items   int
+	strings int
+	nested  int
+	lines   int
+}
+
+// String summarizes elided content, or returns "" if nothing was elided.
+func (e elisions) String() string {
+	var parts []string
+	if e.items > 0 {
+		parts = append(parts, fmt.Sprintf("%d items", e.items))
+	}
+	if e.strings > 0 {
+		parts = append(parts, fmt.Sprintf("%d strings shortened", e.strings))
+	}
+	if e.nested > 0 {
+		parts = append(parts, fmt.Sprintf("%d nested values", e.nested))
+	}
+	if e.lines > 0 {
+		parts = append(parts, fmt.Sprintf("%d lines", e.lines))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// budgetSteps are progressively tighter limits tried when output exceeds
+// its budget: max slice items and max string length.
+var budgetSteps = []struct{ items, stringLen int }{
+	{0, 0},
+	{20, 200},
+	{10, 120},
+	{5, 80},
+	{3, 40},
+	{1, 40},
+}
+
+// elisionLine returns the ELIDED summary line, or "" if nothing was elided.
+func elisionLine(e elisions) string {
+	summary := e.String()
+	if summary == "" {
+		return ""
+	}
+	return "ELIDED: " + summary + "\n"
+}
+
+// visit identifies a pointer or map being rendered. The type is part of the
+// key because a struct and its first field share an address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// renderer holds the state of a single render.
+type renderer struct {
+	formatter    *Formatter
+	maxDepth     int
+	maxItems     int // 0 means unlimited
+	maxStringLen int // 0 means unlimited
+
+	elided elisions
+
+	// path holds the labels leading to the value being rendered, and active
+	// maps each pointer and map on that path to the label it was entered at,
+	// so cycles render as references instead of recursing.
+	path   []string
+	active map[visit]string
+}
+
+// truncated returns the marker for content beyond the depth limit.
+func (r *renderer) truncated() string {
+	r.elided.nested++
+	return "..."
+}
+
+// push appends a field label, map key or "[i]" index to the current path.
+func (r *renderer) push(label string) {
+	r.path = append(r.path, label)
+}
+
+func (r *renderer) pop() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+// label returns the current path, e.g. "CHILDREN[0].PARENT", or ROOT at the
+// top level.
+func (r *renderer) label() string {
+	var sb strings.Builder
+	for _, part := range r.path {
+		if sb.Len() > 0 && !strings.HasPrefix(part, "[") {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(part)
+	}
+	if sb.Len() == 0 {
+		return "ROOT"
+	}
+	return sb.String()
+}
+
+// enter marks the pointer or map v as being rendered at the current path.
+// If an ancestor is already rendering it, enter returns a back-reference to
+// that ancestor and false; otherwise the caller must call leave when done.
+func (r *renderer) enter(v reflect.Value) (string, bool) {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if label, ok := r.active[key]; ok {
+		return "<ref " + label + ">", false
+	}
+	r.active[key] = r.label()
+	return "", true
+}
+
+func (r *renderer) leave(v reflect.Value) {
+	delete(r.active, visit{ptr: v.Pointer(), typ: v.Type()})
+}
+
+// format converts any data to LLM-friendly string format.
+func (r *renderer) format(data interface{}, depth int) string {
+	if data == nil {
+		return ""
+	}
+	if depth > r.maxDepth {
+		return r.truncated()
+	}
+
+	v := reflect.ValueOf(data)
+	return r.formatValue(v, depth)
+}
+
+// formatValue handles reflect.Value formatting.
+func (r *renderer) formatValue(v reflect.Value, depth int) string {
+	// Dereference pointers and interfaces
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		if v.Kind() == reflect.Ptr {
+			ref, ok := r.enter(v)
+			if !ok {
+				return ref
+			}
+			defer r.leave(v)
+		}
+		v = v.Elem()
+	}
+
+	// Types rendering themselves take precedence
+	if m, ok := marshaler(v); ok {
+		return r.formatMarshaled(m.MarshalLLM(), depth)
+	}
+
+	// Check for special types first
+	if formatted, ok := formatSpecialType(v); ok {
+		return formatted
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return r.formatStruct(v, depth)
+	case reflect.Slice, reflect.Array:
+		return r.formatSlice(v, depth)
+	case reflect.Map:
+		return r.formatMap(v, depth)
+	case reflect.String:
+		return r.formatString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			return "true"
+		}
+		return ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		if v.Float() == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%g", v.Float())
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
+
+// formatStruct formats a struct with UPPER_CASE field labels.
+func (r *renderer) formatStruct(v reflect.Value, depth int) string {
+	if depth > r.maxDepth {
+		return r.truncated()
+	}
+
+	var sb strings.Builder
+	t := v.Type()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+
+		// Skip unexported fields
+		if !field.IsExported() {
+			continue
+		}
+
+		opts := parseTag(field)
+		if opts.omit {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+
+		// Inline fields are rendered at the parent's level without a label
+		if opts.inline {
+			if formatted, ok := r.formatInline(fieldValue, depth); ok {
+				sb.WriteString(formatted)
+				continue
+			}
+		}
+
+		// Skip empty/zero fields
+		if !opts.keepZero && shouldSkipField(fieldValue) {
+			continue
+		}
+
+		fieldLabel := opts.label
+		if fieldLabel == "" {
+			fieldLabel = Label(field.Name)
+		}
+		r.push(fieldLabel)
+		formatted := r.formatValue(fieldValue, depth+1)
+		r.pop()
+		if formatted == "" && opts.keepZero {
+			formatted = zeroLiteral(fieldValue)
+		}
+
+		if formatted == "" {
+			continue
+		}
+
+		indent := r.formatter.Indent(depth)
+
+		// Multi-line values (nested structs/slices) get indented on next line
+		if strings.Contains(formatted, "\n") {
+			sb.WriteString(fmt.Sprintf("%s%s:\n%s", indent, fieldLabel, formatted))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s%s: %s\n", indent, fieldLabel, formatted))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatInline renders a struct or map field tagged inline at the parent's
+// level. It reports false for other kinds, which are rendered normally.
+func (r *renderer) formatInline(v reflect.Value, depth int) (string, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", false
+		}
+		if v.Kind() == reflect.Ptr {
+			// An inlined cycle has no label to refer back to; drop it
+			if _, ok := r.enter(v); !ok {
+				return "", true
+			}
+			defer r.leave(v)
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return r.formatStruct(v, depth), true
+	case reflect.Map:
+		return r.formatMap(v, depth), true
+	default:
+		return "", false
+	}
+}
+
+// formatSlice formats slices - primitives with pipe separator, structs with indices.
+func (r *renderer) formatSlice(v reflect.Value, depth int) string {
+	if v.Len() == 0 {
+		return ""
+	}
+
+	if depth > r.maxDepth {
+		return r.truncated()
+	}
+
+	var sb strings.Builder
+
+	n := v.Len()
+	more := 0
+	if r.maxItems > 0 && n > r.maxItems {
+		more = n - r.maxItems
+		n = r.maxItems
+		r.elided.items += more
+	}
+
+	// Check if it's a slice of primitives
+	if !isComplexType(v.Index(0)) {
+		var items []string
+		for i := 0; i < n; i++ {
+			r.push(fmt.Sprintf("[%d]", i))
+			formatted := r.formatValue(v.Index(i), depth)
+			r.pop()
+			if formatted != "" {
+				items = append(items, formatted)
+			}
+		}
+		if more > 0 {
+			items = append(items, moreItems(more))
+		}
+		if len(items) == 0 {
+			return ""
+		}
+		return strings.Join(items, " | ")
+	}
+
+	// For struct slices, use numbered items
+	indent := r.formatter.Indent(depth)
+	for i := 0; i < n; i++ {
+		r.push(fmt.Sprintf("[%d]", i))
+		formatted := r.formatValue(v.Index(i), depth+1)
+		r.pop()
+
+		switch {
+		case formatted == "":
+		case strings.Contains(formatted, "\n"):
+			sb.WriteString(fmt.Sprintf("%s[%d]\n%s", indent, i, formatted))
+		default:
+			// Back-references and marshaled values fit on the index line
+			sb.WriteString(fmt.Sprintf("%s[%d] %s\n", indent, i, formatted))
+		}
+	}
+	if more > 0 {
+		sb.WriteString(fmt.Sprintf("%s%s\n", indent, moreItems(more)))
+	}
+
+	return sb.String()
+}
+
+// formatMap formats maps with key-value pairs.
+func (r *renderer) formatMap(v reflect.Value, depth int) string {
+	if v.Len() == 0 {
+		return ""
+	}
+
+	if depth > r.maxDepth {
+		return r.truncated()
+	}
+
+	ref, ok := r.enter(v)
+	if !ok {
+		return ref
+	}
+	defer r.leave(v)
+
+	// Sort keys by their rendered form so output is stable across runs
+	keys := v.MapKeys()
+	keyStrs := make([]string, len(keys))
+	order := make([]int, len(keys))
+	for i, key := range keys {
+		keyStrs[i] = r.formatValue(key, depth)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return keyStrs[order[a]] < keyStrs[order[b]]
+	})
+
+	var sb strings.Builder
+	for _, i := range order {
+		keyStr := keyStrs[i]
+		r.push(keyStr)
+		valueStr := r.formatValue(v.MapIndex(keys[i]), depth+1)
+		r.pop()
+
+		if valueStr == "" {
+			continue
+		}
+
+		indent := r.formatter.Indent(depth)
+
+		if strings.Contains(valueStr, "\n") {
+			sb.WriteString(fmt.Sprintf("%s%s:\n%s", indent, keyStr, valueStr))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s%s: %s\n", indent, keyStr, valueStr))
+		}
+	}
+
+	return sb.String()
+}
+
+// formatString shortens strings longer than maxStringLen.
+func (r *renderer) formatString(s string) string {
+	if r.maxStringLen > 0 && utf8.RuneCountInString(s) > r.maxStringLen {
+		r.elided.strings++
+		return truncate(s, r.maxStringLen)
+	}
+	return s
+}
+
+// formatMarshaled indents multi-line Marshaler output to the given depth.
+func (r *renderer) formatMarshaled(s string, depth int) string {
+	s = strings.TrimRight(s, "\n")
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	indent := r.formatter.Indent(depth)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// shouldSkipField returns true if the field should be omitted from output.
+func shouldSkipField(v reflect.Value) bool {
+	// Dereference pointers
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+
+	// Let marshalers decide for themselves by returning an empty string
+	if _, ok := marshaler(v); ok {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String() == ""
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	case reflect.Struct:
+		// Special handling for time.Time
+		if t, ok := v.Interface().(time.Time); ok {
+			return t.IsZero()
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// formatSpecialType handles special types like time.Time, time.Duration.
+func formatSpecialType(v reflect.Value) (string, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return "", false
+	}
+
+	iface := v.Interface()
+
+	// time.Time
+	if t, ok := iface.(time.Time); ok {
+		if t.IsZero() {
+			return "", true
+		}
+		return t.Format(time.RFC3339), true
+	}
+
+	// time.Duration
+	if d, ok := iface.(time.Duration); ok {
+		if d == 0 {
+			return "", true
+		}
+		return d.String(), true
+	}
+
+	// []byte - show hex
+	if b, ok := iface.([]byte); ok {
+		if len(b) == 0 {
+			return "", true
+		}
+		if len(b) > 32 {
+			return fmt.Sprintf("%x...(%d bytes)", b[:32], len(b)), true
+		}
+		return fmt.Sprintf("%x", b), true
+	}
+
+	// error interface
+	if err, ok := iface.(error); ok {
+		if err == nil {
+			return "", true
+		}
+		return err.Error(), true
+	}
+
+	return "", false
+}
+
+// moreItems returns the marker for slice items beyond maxItems.
+func moreItems(n int) string {
+	return fmt.Sprintf("%s %d more items", ellipsis, n)
+}
+
+// zeroLiteral renders a zero scalar for fields tagged keepzero.
+func zeroLiteral(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return "false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String()
+		}
+		return "0"
+	case reflect.String:
+		return `""`
+	default:
+		return ""
+	}
+}
+
+// marshaler returns v as a Marshaler, also checking pointer receivers of
+// addressable values.
+func marshaler(v reflect.Value) (Marshaler, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	if m, ok := v.Interface().(Marshaler); ok {
+		return m, true
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(Marshaler); ok {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// isComplexType checks if the value is a struct, slice, or map.
+func isComplexType(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if _, ok := marshaler(v); ok {
+		return false
+	}
+	if _, ok := formatSpecialType(v); ok {
+		return false
+	}
+	k := v.Kind()
+	return k == reflect.Struct || k == reflect.Slice || k == reflect.Array || k == reflect.Map
+}
+
+// truncate shortens s to at most width runes, ending with an ellipsis.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return ellipsis
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
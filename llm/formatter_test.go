@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Name", "NAME"},
+		{"FieldName", "FIELD_NAME"},
+		{"HTTPStatus", "HTTP_STATUS"},
+		{"ID", "ID"},
+		{"URLPath", "URL_PATH"},
+		{"XMLParser", "XML_PARSER"},
+	}
+
+	for _, tt := range tests {
+		result := Label(tt.input)
+		if result != tt.expected {
+			t.Errorf("Label(%s) = %s, want %s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+type node struct {
+	Name     string
+	Parent   *node
+	Children []*node
+}
+
+func TestFormat_Cycles(t *testing.T) {
+	root := &node{Name: "root"}
+	child := &node{Name: "child", Parent: root}
+	root.Children = []*node{child}
+	child.Children = []*node{child}
+
+	output := Format(root)
+
+	expected := `NAME: root
+CHILDREN:
+  [0]
+    NAME: child
+    PARENT: <ref ROOT>
+    CHILDREN:
+      [0] <ref CHILDREN[0]>
+`
+	if output != expected {
+		t.Errorf("unexpected output.\nGot:\n%s\nExpected:\n%s", output, expected)
+	}
+}
+
+func TestFormat_SharedPointerIsNotACycle(t *testing.T) {
+	type pair struct {
+		Left  *node
+		Right *node
+	}
+	shared := &node{Name: "shared"}
+
+	output := Format(pair{Left: shared, Right: shared})
+
+	if strings.Contains(output, "<ref") {
+		t.Errorf("siblings sharing a pointer should both render, got:\n%s", output)
+	}
+	if strings.Count(output, "NAME: shared") != 2 {
+		t.Errorf("expected shared node twice, got:\n%s", output)
+	}
+}
+
+func TestFormat_MapCycle(t *testing.T) {
+	m := map[string]interface{}{"name": "loop"}
+	m["self"] = m
+
+	output := Format(m)
+
+	if !strings.Contains(output, "self: <ref ROOT>") {
+		t.Errorf("expected map back-reference, got:\n%s", output)
+	}
+}
+
+func TestFormatter_Options(t *testing.T) {
+	type Inner struct{ Value string }
+	type Outer struct {
+		Name  string
+		Inner Inner
+	}
+	data := Outer{Name: "x", Inner: Inner{Value: "y"}}
+
+	output := NewFormatter(Options{IndentSize: 4}).Format(data)
+	if !strings.Contains(output, "INNER:\n    VALUE: y\n") {
+		t.Errorf("expected 4-space indent, got:\n%s", output)
+	}
+
+	output = NewFormatter(Options{MaxDepth: -1}).Format(data)
+	if !strings.Contains(output, "INNER: ...") {
+		t.Errorf("expected nested struct cut at top level, got:\n%s", output)
+	}
+}
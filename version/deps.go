@@ -0,0 +1,138 @@
+package version
+
+import (
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Dependency is a module the binary was built with.
+type Dependency struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version" yaml:"version"`
+	// Replace is the replacement module, e.g. "../fork" or
+	// "example.com/fork v1.2.4", if the dependency was replaced.
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// String returns "path version", followed by " => replacement" if replaced.
+func (d Dependency) String() string {
+	s := d.Path + " " + d.Version
+	if d.Replace != "" {
+		s += " => " + d.Replace
+	}
+	return s
+}
+
+// Dependencies returns the module dependencies recorded in the binary's
+// embedded build info, or nil if there is none.
+func Dependencies() []Dependency {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	deps := make([]Dependency, 0, len(bi.Deps))
+	for _, m := range bi.Deps {
+		dep := Dependency{Path: m.Path, Version: m.Version}
+		if r := m.Replace; r != nil {
+			dep.Replace = strings.TrimSpace(r.Path + " " + r.Version)
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// Compare compares two semantic versions, with or without a "v" prefix,
+// returning -1, 0 or +1. Pre-releases sort before their release, and
+// versions that cannot be parsed (such as "dev") sort before all others.
+func Compare(a, b string) int {
+	pa, oka := parseSemver(a)
+	pb, okb := parseSemver(b)
+	switch {
+	case !oka && !okb:
+		return 0
+	case !oka:
+		return -1
+	case !okb:
+		return 1
+	}
+
+	for i := 0; i < 3; i++ {
+		if pa.nums[i] != pb.nums[i] {
+			if pa.nums[i] < pb.nums[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case pa.pre == pb.pre:
+		return 0
+	case pa.pre == "":
+		return 1
+	case pb.pre == "":
+		return -1
+	default:
+		return comparePrerelease(pa.pre, pb.pre)
+	}
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers,
+// numerically where both are numbers, e.g. rc.2 < rc.10.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		na, errA := strconv.Atoi(as[i])
+		nb, errB := strconv.Atoi(bs[i])
+		switch {
+		case errA == nil && errB == nil && na < nb:
+			return -1
+		case errA == nil && errB == nil:
+			return 1
+		case errA == nil: // numeric identifiers sort first
+			return -1
+		case errB == nil:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+type semver struct {
+	nums [3]int
+	pre  string
+}
+
+// parseSemver parses "v1.2.3-pre+build"; minor and patch may be omitted.
+func parseSemver(s string) (semver, bool) {
+	var v semver
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, v.pre, _ = strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.nums[i] = n
+	}
+	return v, true
+}
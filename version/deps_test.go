@@ -0,0 +1,62 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.10.0", "1.9.9", 1},
+		{"2", "1.9", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"dev", "0.0.1", -1},
+		{"dev", "unknown", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestInfo_String_Dependencies(t *testing.T) {
+	info := Info{
+		Version: "1.0.0",
+		Dependencies: []Dependency{
+			{Path: "github.com/spf13/cobra", Version: "v1.10.2"},
+			{Path: "example.com/lib", Version: "v0.1.0", Replace: "../lib"},
+		},
+	}
+
+	s := info.String()
+	for _, want := range []string{
+		"Dependencies:\n  github.com/spf13/cobra v1.10.2",
+		"example.com/lib v0.1.0 => ../lib",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected %q in output:\n%s", want, s)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	for _, dep := range Dependencies() {
+		if dep.Path == "" || dep.Version == "" && dep.Replace == "" {
+			t.Errorf("incomplete dependency: %+v", dep)
+		}
+	}
+}
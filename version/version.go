@@ -4,6 +4,7 @@ package version
 import (
 	"fmt"
 	"runtime"
+	"runtime/debug"
 )
 
 // These variables are set at build time via ldflags.
@@ -23,17 +24,44 @@ type Info struct {
 	BuildDate string `json:"build_date" yaml:"build_date"`
 	GoVersion string `json:"go_version" yaml:"go_version"`
 	Platform  string `json:"platform" yaml:"platform"`
+
+	// LatestVersion is the newest released version, when an update check
+	// found one. It is not included in String.
+	LatestVersion string `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`
+	// Dependencies are the modules the binary was built with. Get leaves
+	// it empty; see Dependencies.
+	Dependencies []Dependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
-// Get returns the current version information.
+// Get returns the current version information. Values not set via ldflags
+// are taken from the build info embedded by the go command when available:
+// the module version for "go install pkg@version" builds, and the VCS
+// revision and time for builds from a checkout.
 func Get() Info {
-	return Info{
+	info := Info{
 		Version:   Version,
 		GitCommit: GitCommit,
 		BuildDate: BuildDate,
 		GoVersion: runtime.Version(),
 		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, setting := range bi.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.GitCommit == "unknown":
+			info.GitCommit = setting.Value
+		case setting.Key == "vcs.time" && info.BuildDate == "unknown":
+			info.BuildDate = setting.Value
+		}
+	}
+	return info
 }
 
 // String returns a formatted version string.
@@ -45,8 +73,18 @@ func (i Info) String() string {
 	if i.BuildDate != "" && i.BuildDate != "unknown" {
 		s += fmt.Sprintf("\nBuild Date: %s", i.BuildDate)
 	}
-	s += fmt.Sprintf("\nGo Version: %s", i.GoVersion)
-	s += fmt.Sprintf("\nPlatform:   %s", i.Platform)
+	if i.GoVersion != "" {
+		s += fmt.Sprintf("\nGo Version: %s", i.GoVersion)
+	}
+	if i.Platform != "" {
+		s += fmt.Sprintf("\nPlatform:   %s", i.Platform)
+	}
+	if len(i.Dependencies) > 0 {
+		s += "\nDependencies:"
+		for _, dep := range i.Dependencies {
+			s += "\n  " + dep.String()
+		}
+	}
 	return s
 }
 